@@ -1,33 +1,109 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database/migrations"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database/seeds"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/delivery/routes"
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/app"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/logger"
+	"gorm.io/gorm"
 )
 
+const usage = `Usage: golang-clean-architecture [command]
+
+Commands:
+  serve                          run the HTTP API (the default)
+  migrate up                     apply every pending migration
+  migrate down [N]               revert the last N applied migrations (default 1)
+  migrate status                 list migrations and whether they are applied
+  migrate force V                mark version V as applied and clean without running SQL
+  seed                           create the built-in roles and the admin user
+  user create [flags]            create a verified user; the password is read from stdin
+  user list [flags]              list active users; disabled ones are left out
+  user disable <id|email>        deactivate a user and end its sessions
+  user set-password <id|email>   replace a password read from stdin and end its sessions
+  token issue <id|email>         print an access token for a user
+  help                           show this help
+
+Commands taking <id|email> find disabled users as well.
+Run "<command> -h" for the flags of a command.
+`
+
+// command runs a subcommand with the arguments that follow its name.
+type command func(ctx context.Context, cfg *config.Config, args []string) error
+
+var commands = map[string]command{
+	"serve":   serve,
+	"migrate": migrate,
+	"seed":    seed,
+	"user":    user,
+	"token":   token,
+}
+
 func main() {
-	// Load environment variables from .env file, if it exists
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, relying on environment variables")
+	args := os.Args[1:]
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Print(usage)
+		return
+	}
+
+	run, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		os.Exit(2)
 	}
 
-	// Run database migrations to update schema
-	migrations.Migrate()
+	// Load the configuration from the environment, .env and CONFIG_FILE
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("invalid configuration", "error", err)
+	}
+
+	// Every package logs through slog's default logger from here on
+	if _, err := logger.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logger.Fatal("failed to set up logging", "error", err)
+	}
+
+	// Stop on SIGINT or SIGTERM: the server drains its requests, the other
+	// commands stop between statements
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err = run(ctx, cfg, args[1:])
+	stop()
+
+	if err != nil {
+		logger.Fatal(args[0]+" failed", "error", err)
+	}
+}
 
-	// Seed the database with initial data (e.g., admin user)
-	seeds.Seed()
+// newApp connects to the database and wires the application on top of it.
+// The caller closes a.DB once done.
+func newApp(cfg *config.Config) (*app.App, error) {
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect database: %w", err)
+	}
 
-	// Initialize the Gin router and register all application routes
-	r := gin.Default()
-	routes.RegisterRoutes(r)
+	a, err := app.New(cfg, db)
+	if err != nil {
+		database.Close(db)
+		return nil, err
+	}
+	return a, nil
+}
 
-	// Start the HTTP server on port 8080
-	if err := r.Run(":8080"); err != nil {
-		panic(err)
+// closeDB releases the database connections once nothing can use them.
+func closeDB(db *gorm.DB) {
+	if err := database.Close(db); err != nil {
+		slog.Error("failed to close database", "error", err)
 	}
 }
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+)
+
+func seed(ctx context.Context, cfg *config.Config, args []string) error {
+	if err := flag.NewFlagSet("seed", flag.ExitOnError).Parse(args); err != nil {
+		return err
+	}
+
+	application, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB(application.DB)
+
+	return application.Seed(ctx)
+}
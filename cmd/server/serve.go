@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database/migrations"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/server"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/tracing"
+)
+
+func serve(ctx context.Context, cfg *config.Config, args []string) error {
+	if err := flag.NewFlagSet("serve", flag.ExitOnError).Parse(args); err != nil {
+		return err
+	}
+
+	// Export spans of every request, use case and query
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		FilePath:     cfg.Tracing.FilePath,
+		ServiceName:  cfg.Tracing.ServiceName,
+		Environment:  cfg.Env,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		// Flush the spans still buffered by the exporter
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	application, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB(application.DB)
+
+	// Bring the schema up to date, unless deployments run `migrate up` themselves
+	if cfg.Database.AutoMigrate {
+		migrator, err := migrations.New(application.DB, cfg.Database.Type)
+		if err != nil {
+			return fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	// Seed the database with initial data (e.g., admin user), unless
+	// deployments run `seed` themselves
+	if cfg.Database.AutoSeed {
+		if err := application.Seed(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Rotate the JWT signing keys on SIGHUP
+	stopReload := reloadKeysOnSignal(application.JWTUseCase)
+	defer stopReload()
+
+	// Serve until SIGINT or SIGTERM, then drain in-flight requests
+	if err := server.Run(ctx, server.New(cfg.Server, application.NewRouter()), cfg.Server); err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	slog.Info("server stopped")
+	return nil
+}
+
+// reloadKeysOnSignal rotates the JWT key ring whenever the process receives
+// SIGHUP, after the key files or the config file have been changed on disk.
+// The returned func stops listening for the signal.
+func reloadKeysOnSignal(jwtUseCase jwt_usecase.IJWTUseCase) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			cfg, err := config.Load()
+			if err != nil {
+				slog.Error("failed to reload configuration", "error", err)
+				continue
+			}
+			if err := jwtUseCase.ReloadKeys(cfg.JWT); err != nil {
+				slog.Error("failed to reload JWT signing keys", "error", err)
+				continue
+			}
+			slog.Info("JWT signing keys reloaded")
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+)
+
+func token(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) != 2 || args[0] != "issue" {
+		return fmt.Errorf("token needs issue and a user id or email")
+	}
+
+	application, err := newApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB(application.DB)
+
+	target, err := findUser(ctx, application.UserUseCase, args[1])
+	if err != nil {
+		return err
+	}
+
+	roles, err := application.UserUseCase.GetUserRoles(ctx, target.ID)
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := application.JWTUseCase.GenerateToken(target.ID, roles)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(accessToken)
+	return nil
+}
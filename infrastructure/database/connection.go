@@ -1,18 +1,19 @@
 package database
 
 import (
-	"log"
-	"os"
-	"sync"
+	"context"
+	"fmt"
+	"log/slog"
 
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/logger"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/metrics"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/tracing"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-)
-
-var (
-	dbInstance *gorm.DB
-	once       sync.Once
+	gormlogger "gorm.io/gorm/logger"
 )
 
 type Database interface {
@@ -20,41 +21,116 @@ type Database interface {
 }
 
 type MySQLDatabase struct {
-	DSN string
+	DSN    string
+	Logger gormlogger.Interface
 }
 
 func (m *MySQLDatabase) Connect() (*gorm.DB, error) {
-	return gorm.Open(mysql.Open(m.DSN), &gorm.Config{})
+	return gorm.Open(mysql.Open(m.DSN), &gorm.Config{Logger: m.Logger})
 }
 
 type PostgresDatabase struct {
-	DSN string
+	DSN    string
+	Logger gormlogger.Interface
 }
 
 func (p *PostgresDatabase) Connect() (*gorm.DB, error) {
-	return gorm.Open(postgres.Open(p.DSN), &gorm.Config{})
-}
-
-func GetDBInstance() *gorm.DB {
-	once.Do(func() {
-		var dbConn Database
-		dbType := os.Getenv("DB_TYPE")
-		dsn := os.Getenv("DB_DSN")
-
-		switch dbType {
-		case "mysql":
-			dbConn = &MySQLDatabase{DSN: dsn}
-		case "postgres":
-			dbConn = &PostgresDatabase{DSN: dsn}
-		default:
-			log.Fatal("Unsupported database type:", dbType)
-		}
+	return gorm.Open(postgres.Open(p.DSN), &gorm.Config{Logger: p.Logger})
+}
+
+// SQLiteDatabase opens a database file, or a private in-memory database
+// when DSN is ":memory:". It needs no server, so the API runs anywhere.
+type SQLiteDatabase struct {
+	DSN    string
+	Logger gormlogger.Interface
+}
 
-		db, err := dbConn.Connect()
-		if err != nil {
-			log.Fatal("failed to connect database:", err)
+func (s *SQLiteDatabase) Connect() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(s.DSN), &gorm.Config{Logger: s.Logger})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows one writer at a time, and every connection to :memory:
+	// opens a database of its own, so the pool keeps a single connection
+	// that never expires.
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	for _, pragma := range []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA busy_timeout = 5000",
+		"PRAGMA journal_mode = WAL",
+	} {
+		if err := db.Exec(pragma).Error; err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("%s: %w", pragma, err)
 		}
-		dbInstance = db
-	})
-	return dbInstance
+	}
+
+	return db, nil
+}
+
+// Open connects to the database described by cfg. Every call returns a new
+// connection pool, owned by the caller.
+func Open(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	queryLogger := logger.NewGormLogger(slog.Default(), cfg.SlowQueryThreshold)
+
+	var dbConn Database
+	switch cfg.Type {
+	case "mysql":
+		dbConn = &MySQLDatabase{DSN: cfg.DSN, Logger: queryLogger}
+	case "postgres":
+		dbConn = &PostgresDatabase{DSN: cfg.DSN, Logger: queryLogger}
+	case "sqlite":
+		dbConn = &SQLiteDatabase{DSN: cfg.DSN, Logger: queryLogger}
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", cfg.Type)
+	}
+
+	return dbConn.Connect()
+}
+
+// Instrument reports the duration and errors of every query on db, and its
+// connection pool stats, to m.
+func Instrument(db *gorm.DB, m *metrics.Metrics) error {
+	if err := db.Use(metrics.NewGormPlugin(m)); err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return m.RegisterDBStats("main", sqlDB)
+}
+
+// Trace wraps every query on db in a span of the trace in its context.
+func Trace(db *gorm.DB, dbSystem string) error {
+	return db.Use(tracing.NewGormPlugin(dbSystem))
+}
+
+// Close closes the connection pool opened by Open.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Ping checks that the database still answers.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// lockName identifies the migration lock; every instance must agree on it.
+const lockName = "schema_migrations"
+
+// lockWait is how long an instance waits for another one to finish migrating.
+const lockWait = 10 * time.Minute
+
+var ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// acquireLock takes a session level advisory lock on conn, so only one
+// instance migrates at a time. The lock lives as long as conn; call the
+// returned function to release it.
+func acquireLock(ctx context.Context, conn *sql.Conn, dialect string) (func() error, error) {
+	switch dialect {
+	case "mysql":
+		var acquired sql.NullInt64
+		seconds := int(math.Ceil(lockWait.Seconds()))
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, seconds).Scan(&acquired); err != nil {
+			return nil, err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return nil, ErrLockTimeout
+		}
+		return func() error {
+			_, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+			return err
+		}, nil
+
+	case "postgres":
+		lockCtx, cancel := context.WithTimeout(ctx, lockWait)
+		defer cancel()
+		if _, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock(hashtext($1))", lockName); err != nil {
+			if errors.Is(lockCtx.Err(), context.DeadlineExceeded) {
+				return nil, ErrLockTimeout
+			}
+			return nil, err
+		}
+		return func() error {
+			_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+			return err
+		}, nil
+
+	case "sqlite":
+		// SQLite has no advisory locks. Its DDL is transactional and the pool
+		// holds a single connection, so a concurrent migrator in another
+		// process waits on the database lock and then fails on the version it
+		// tries to record again, leaving the schema intact.
+		return func() error { return nil }, nil
+
+	default:
+		return nil, fmt.Errorf("migration lock not supported for database type %q", dialect)
+	}
+}
@@ -1,19 +1,384 @@
 package migrations
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"gorm.io/gorm"
 )
 
-func Migrate() {
-	dbConn := database.GetDBInstance()
+const createHistoryTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    dirty BOOLEAN NOT NULL DEFAULT FALSE,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (version)
+)`
 
-	if err := dbConn.AutoMigrate(&domain.User{}); err != nil {
-		log.Fatal("failed to migrate database:", err)
-		return
+// DirtyError reports a migration that failed halfway. MySQL commits DDL
+// statement by statement, so the schema has to be repaired by hand before
+// Force marks the version clean again.
+type DirtyError struct {
+	Version int64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("migration %d is dirty; repair the schema by hand, then run force", e.Version)
+}
+
+// MigrationStatus is one line of the migration history. A migration that is
+// applied but has no file in this build is reported with Missing set.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Dirty     bool
+	Missing   bool
+}
+
+// Migrator applies the versioned SQL files in sql/<dialect> and records every
+// applied version in the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// New returns a migrator for db; dialect is the database type, mysql,
+// postgres or sqlite.
+func New(db *gorm.DB, dialect string) (*Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := load(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: sqlDB, dialect: dialect, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in version order and returns how many
+// it applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		history, err := m.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(history); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := history[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the n most recently applied migrations and returns how many it
+// reverted.
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("down needs a positive number of migrations, got %d", n)
+	}
+
+	reverted := 0
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		history, err := m.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(history); err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(history))
+		for version := range history {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions {
+			if reverted == n {
+				break
+			}
+			migration := m.find(version)
+			if migration == nil {
+				return fmt.Errorf("migration %d is applied but has no file in this build", version)
+			}
+			if err := m.revert(ctx, conn, *migration); err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Force records version as the current schema version without running any
+// SQL: every known migration up to it is marked applied and clean, and every
+// later one as not applied. Version 0 clears the history.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx, m.bind("DELETE FROM schema_migrations WHERE version > ?"), version); err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, m.bind("UPDATE schema_migrations SET dirty = ? WHERE version <= ?"), false, version); err != nil {
+			return err
+		}
+
+		history, err := m.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := history[migration.Version]; ok {
+				continue
+			}
+			if err := m.record(ctx, conn, migration, false); err != nil {
+				return err
+			}
+		}
+
+		slog.Info("forced migration version", "version", version)
+		return nil
+	})
+}
+
+// Status lists every known migration and whether it is applied, including
+// applied versions this build has no file for.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, createHistoryTable); err != nil {
+		return nil, err
+	}
+
+	history, err := m.history(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if applied, ok := history[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = applied.AppliedAt
+			status.Dirty = applied.Dirty
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, applied := range history {
+		if m.find(applied.Version) == nil {
+			applied.Missing = true
+			statuses = append(statuses, applied)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	release, err := acquireLock(ctx, conn, m.dialect)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if err := release(); err != nil {
+			slog.Error("failed to release the migration lock", "error", err)
+		}
+	}()
 
-	log.Println("Database migration completed.")
+	if _, err := conn.ExecContext(ctx, createHistoryTable); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// apply runs a migration. Postgres and SQLite run DDL in transactions, so a
+// failure leaves no trace; MySQL cannot, so the version is recorded dirty
+// first and only marked clean once every statement succeeded.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	slog.Info("applying migration", "version", migration.Version, "name", migration.Name)
+
+	if m.transactionalDDL() {
+		return m.inTx(ctx, conn, func(tx *sql.Tx) error {
+			if err := run(ctx, tx, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			_, err := tx.ExecContext(ctx, m.bind("INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, ?)"), migration.Version, migration.Name, false)
+			return err
+		})
+	}
+
+	if err := m.record(ctx, conn, migration, true); err != nil {
+		return err
+	}
+	if err := run(ctx, conn, migration.Up); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	_, err := conn.ExecContext(ctx, m.bind("UPDATE schema_migrations SET dirty = ? WHERE version = ?"), false, migration.Version)
+	return err
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	slog.Info("reverting migration", "version", migration.Version, "name", migration.Name)
+
+	if m.transactionalDDL() {
+		return m.inTx(ctx, conn, func(tx *sql.Tx) error {
+			if err := run(ctx, tx, migration.Down); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			_, err := tx.ExecContext(ctx, m.bind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version)
+			return err
+		})
+	}
+
+	if _, err := conn.ExecContext(ctx, m.bind("UPDATE schema_migrations SET dirty = ? WHERE version = ?"), true, migration.Version); err != nil {
+		return err
+	}
+	if err := run(ctx, conn, migration.Down); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	_, err := conn.ExecContext(ctx, m.bind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version)
+	return err
+}
+
+func (m *Migrator) record(ctx context.Context, conn *sql.Conn, migration Migration, dirty bool) error {
+	_, err := conn.ExecContext(ctx, m.bind("INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, ?)"), migration.Version, migration.Name, dirty)
+	return err
+}
+
+func (m *Migrator) history(ctx context.Context, conn *sql.Conn) (map[int64]MigrationStatus, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, dirty, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make(map[int64]MigrationStatus)
+	for rows.Next() {
+		var status MigrationStatus
+		var appliedAt time.Time
+		if err := rows.Scan(&status.Version, &status.Name, &status.Dirty, &appliedAt); err != nil {
+			return nil, err
+		}
+		status.Applied = true
+		status.AppliedAt = &appliedAt
+		history[status.Version] = status
+	}
+
+	return history, rows.Err()
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) transactionalDDL() bool {
+	return m.dialect == "postgres" || m.dialect == "sqlite"
+}
+
+// bind rewrites ? placeholders into the $n form Postgres expects.
+func (m *Migrator) bind(query string) string {
+	if m.dialect != "postgres" {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func run(ctx context.Context, db execer, script string) error {
+	for _, statement := range statements(script) {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkDirty(history map[int64]MigrationStatus) error {
+	for version, status := range history {
+		if status.Dirty {
+			return &DirtyError{Version: version}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (jti),
+    INDEX idx_revoked_tokens_user_id (user_id),
+    INDEX idx_revoked_tokens_expires_at (expires_at)
+);
+
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id CHAR(36) NOT NULL,
+    revoked_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id),
+    INDEX idx_user_token_revocations_expires_at (expires_at)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (id),
+    INDEX idx_recovery_codes_user_id (user_id)
+);
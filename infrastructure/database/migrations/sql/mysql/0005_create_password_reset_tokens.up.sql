@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (id),
+    INDEX idx_password_reset_tokens_user_id (user_id),
+    UNIQUE INDEX idx_password_reset_tokens_token_hash (token_hash)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    scope VARCHAR(10) NOT NULL,
+    subject VARCHAR(64) NOT NULL,
+    failed_attempts BIGINT NOT NULL DEFAULT 0,
+    lockouts BIGINT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP NULL,
+    last_failure_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    PRIMARY KEY (scope, subject)
+);
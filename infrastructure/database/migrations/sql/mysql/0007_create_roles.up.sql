@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_permissions_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id CHAR(36) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_roles_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id CHAR(36) NOT NULL,
+    permission_id CHAR(36) NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id CHAR(36) NOT NULL,
+    role_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (user_id, role_id),
+    INDEX idx_user_roles_role_id (role_id)
+);
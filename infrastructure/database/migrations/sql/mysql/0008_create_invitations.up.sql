@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS invitations (
+    id CHAR(36) NOT NULL,
+    email VARCHAR(155) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    invited_by CHAR(36) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (id),
+    INDEX idx_invitations_email (email),
+    UNIQUE INDEX idx_invitations_token_hash (token_hash)
+);
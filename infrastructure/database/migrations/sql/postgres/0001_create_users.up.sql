@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS users (
+    id CHAR(36) NOT NULL,
+    first_name VARCHAR(155) NOT NULL,
+    last_name VARCHAR(155) NOT NULL,
+    full_name VARCHAR(310) NOT NULL,
+    email VARCHAR(155) NOT NULL,
+    password VARCHAR(155) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    email_verified_at TIMESTAMP NULL,
+    totp_secret VARCHAR(64) NULL,
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_last_used_step BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+);
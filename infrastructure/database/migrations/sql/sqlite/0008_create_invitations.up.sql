@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS invitations (
+    id CHAR(36) NOT NULL,
+    email VARCHAR(155) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    invited_by CHAR(36) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitations_email ON invitations (email);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_token_hash ON invitations (token_hash);
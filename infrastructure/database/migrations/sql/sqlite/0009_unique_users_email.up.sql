@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
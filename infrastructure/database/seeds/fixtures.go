@@ -0,0 +1,107 @@
+package seeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures is the content of a fixture file. Each entity is created when it
+// is missing and left alone otherwise.
+type Fixtures struct {
+	Users []UserFixture `yaml:"users" json:"users"`
+}
+
+// UserFixture is a verified user; Roles replace the default user role.
+type UserFixture struct {
+	Email     string   `yaml:"email" json:"email"`
+	FirstName string   `yaml:"first_name" json:"first_name"`
+	LastName  string   `yaml:"last_name" json:"last_name"`
+	Password  string   `yaml:"password" json:"password"`
+	Roles     []string `yaml:"roles" json:"roles"`
+}
+
+// LoadFixtures reads the .yaml, .yml and .json files of dir and then those of
+// its env subdirectory, if there is one, in file name order.
+func LoadFixtures(dir, env string) (*Fixtures, error) {
+	var fixtures Fixtures
+
+	if err := loadFixtureDir(&fixtures, dir); err != nil {
+		return nil, err
+	}
+
+	if env != "" {
+		envDir := filepath.Join(dir, env)
+		if _, err := os.Stat(envDir); err == nil {
+			if err := loadFixtureDir(&fixtures, envDir); err != nil {
+				return nil, err
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return &fixtures, nil
+}
+
+func loadFixtureDir(fixtures *Fixtures, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read fixtures: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		var file Fixtures
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = decodeFixture(path, func(data []byte) error {
+				decoder := yaml.NewDecoder(bytes.NewReader(data))
+				decoder.KnownFields(true)
+				return decoder.Decode(&file)
+			})
+		case ".json":
+			err = decodeFixture(path, func(data []byte) error {
+				decoder := json.NewDecoder(bytes.NewReader(data))
+				decoder.DisallowUnknownFields()
+				return decoder.Decode(&file)
+			})
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		fixtures.Users = append(fixtures.Users, file.Users...)
+	}
+
+	return nil
+}
+
+func decodeFixture(path string, decode func(data []byte) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read fixture file: %w", err)
+	}
+
+	// An empty file holds no fixtures
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	if err := decode(data); err != nil {
+		return fmt.Errorf("parse fixture file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package seeds
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+)
+
+// minPasswordLength matches the rule the signup endpoint enforces.
+const minPasswordLength = 8
+
+// weakPasswords are defaults shipped in examples and the usual guesses; an
+// account seeded with one of them is a backdoor outside development.
+var weakPasswords = map[string]bool{
+	"test@123":    true,
+	"admin":       true,
+	"admin123":    true,
+	"admin@123":   true,
+	"password":    true,
+	"password1":   true,
+	"password123": true,
+	"changeme":    true,
+	"secret":      true,
+	"12345678":    true,
+	"123456789":   true,
+	"qwerty123":   true,
+}
+
+func checkPassword(cfg *config.Config, email, password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("password of %s must have at least %d characters", email, minPasswordLength)
+	}
+
+	if !weakPasswords[strings.ToLower(password)] {
+		return nil
+	}
+	if !cfg.IsDevelopment() {
+		return fmt.Errorf("refusing to seed %s with a well-known password in the %s environment", email, cfg.Env)
+	}
+
+	slog.Warn("seeding a user with a well-known password, only acceptable in development", "email", email)
+	return nil
+}
@@ -1,37 +1,157 @@
 package seeds
 
 import (
+	"bytes"
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
-	"github.com/google/uuid"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
 )
 
-func Seed() {
-	// Create an admin user object with predefined credentials
-	adminUser := domain.User{
-		ID:        uuid.New().String(),
-		FirstName: "User",
-		LastName:  "Admin",
-		FullName:  "User Admin",
-		Email:     "admin@admin.com",
-		Password:  "test@123",
-	}
-
-	// Initialize the user use case with a MySQL repository implementation
-	userUseCase := user_usecase.NewUserUseCase(mysql.NewUserRepository())
-
-	// Attempt to register the admin user
-	if err := userUseCase.Register(&adminUser); err != nil {
-		if !errors.Is(err, user_usecase.ErrEmailAlreadyRegistered) {
-			log.Fatalf("Failed to register admin user: %v", err)
-			return
+// Seed creates the built-in roles, the bootstrap admin configured in
+// cfg.Seed and the users of the fixture files. It can run on every boot:
+// existing users are left as they are.
+func Seed(ctx context.Context, cfg *config.Config, userUseCase user_usecase.IUserUseCase, roleRepo mysql.IRoleRepository) error {
+	// Create the built-in roles and keep their permissions up to date
+	for role, permissions := range domain.DefaultRolePermissions {
+		if err := roleRepo.Sync(ctx, role, permissions); err != nil {
+			return fmt.Errorf("failed to seed role %s: %w", role, err)
+		}
+	}
+
+	s := &seeder{cfg: cfg, userUseCase: userUseCase, roleRepo: roleRepo}
+
+	if cfg.Seed.AdminEmail != "" {
+		if err := s.seedAdmin(ctx); err != nil {
+			return err
+		}
+	} else {
+		slog.Info("no admin user configured, set SEED_ADMIN_EMAIL to seed one")
+	}
+
+	if cfg.Seed.FixturesDir != "" {
+		fixtures, err := LoadFixtures(cfg.Seed.FixturesDir, cfg.Env)
+		if err != nil {
+			return err
+		}
+		for _, user := range fixtures.Users {
+			if err := s.seedUser(ctx, user); err != nil {
+				return err
+			}
 		}
-		log.Println("Admin user already registered.")
 	}
 
-	log.Println("Seeded successfully.")
+	slog.Info("seeded successfully")
+	return nil
+}
+
+type seeder struct {
+	cfg         *config.Config
+	userUseCase user_usecase.IUserUseCase
+	roleRepo    mysql.IRoleRepository
+}
+
+func (s *seeder) seedAdmin(ctx context.Context) error {
+	password, err := adminPassword(s.cfg.Seed)
+	if err != nil {
+		return err
+	}
+
+	adminUser := domain.User{
+		FirstName: s.cfg.Seed.AdminFirstName,
+		LastName:  s.cfg.Seed.AdminLastName,
+		FullName:  s.cfg.Seed.AdminFirstName + " " + s.cfg.Seed.AdminLastName,
+		Email:     s.cfg.Seed.AdminEmail,
+		Password:  password,
+	}
+
+	created, err := s.register(ctx, &adminUser)
+	if err != nil {
+		return fmt.Errorf("failed to register admin user: %w", err)
+	}
+	if !created {
+		slog.Info("admin user already registered")
+	}
+
+	// Make sure the admin user holds the admin role, even if it was removed
+	if err := s.roleRepo.SetUserRoles(ctx, adminUser.ID, []string{domain.RoleAdmin}); err != nil {
+		return fmt.Errorf("failed to assign admin role: %w", err)
+	}
+
+	return nil
+}
+
+func (s *seeder) seedUser(ctx context.Context, fixture UserFixture) error {
+	user := domain.User{
+		FirstName: fixture.FirstName,
+		LastName:  fixture.LastName,
+		FullName:  fixture.FirstName + " " + fixture.LastName,
+		Email:     fixture.Email,
+		Password:  fixture.Password,
+	}
+
+	created, err := s.register(ctx, &user)
+	if err != nil {
+		return fmt.Errorf("failed to seed user %s: %w", fixture.Email, err)
+	}
+	if !created || len(fixture.Roles) == 0 {
+		return nil
+	}
+
+	if err := s.roleRepo.SetUserRoles(ctx, user.ID, fixture.Roles); err != nil {
+		return fmt.Errorf("failed to assign roles to %s: %w", fixture.Email, err)
+	}
+	return nil
+}
+
+// register creates user unless its email is taken, and reports whether it
+// did. Either way user.ID is set to the stored user.
+func (s *seeder) register(ctx context.Context, user *domain.User) (bool, error) {
+	existing, err := s.userUseCase.GetUserByEmail(ctx, user.Email)
+	if err == nil {
+		user.ID = existing.ID
+		return false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	if err := validator.New().Struct(user); err != nil {
+		return false, err
+	}
+	if err := checkPassword(s.cfg, user.Email, user.Password); err != nil {
+		return false, err
+	}
+
+	// Seeded users have no mailbox to confirm
+	verifiedAt := time.Now()
+	user.EmailVerifiedAt = &verifiedAt
+
+	if err := s.userUseCase.Register(ctx, user); err != nil {
+		return false, err
+	}
+
+	slog.Info("seeded user", "email", user.Email)
+	return true, nil
+}
+
+func adminPassword(cfg config.SeedConfig) (string, error) {
+	if cfg.AdminPasswordFile == "" {
+		return cfg.AdminPassword, nil
+	}
+
+	data, err := os.ReadFile(cfg.AdminPasswordFile)
+	if err != nil {
+		return "", fmt.Errorf("read admin password: %w", err)
+	}
+	return string(bytes.TrimSpace(data)), nil
 }
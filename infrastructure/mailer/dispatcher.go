@@ -0,0 +1,33 @@
+package mailer
+
+import "log/slog"
+
+// Dispatcher sends mail in the background, so response times depend neither
+// on the mail server nor on whether a message was sent at all. At most
+// maxPending messages are in flight; further ones are dropped and logged
+// rather than piling up goroutines.
+type Dispatcher struct {
+	mailer  Mailer
+	pending chan struct{}
+}
+
+func NewDispatcher(m Mailer, maxPending int) *Dispatcher {
+	return &Dispatcher{mailer: m, pending: make(chan struct{}, maxPending)}
+}
+
+// Dispatch hands msg to the mailer without waiting for it to be delivered.
+func (d *Dispatcher) Dispatch(msg Message) {
+	select {
+	case d.pending <- struct{}{}:
+	default:
+		slog.Warn("dropped email, too many emails pending", "subject", msg.Subject)
+		return
+	}
+
+	go func() {
+		defer func() { <-d.pending }()
+		if err := d.mailer.Send(msg); err != nil {
+			slog.Error("failed to send email", "subject", msg.Subject, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// FileMailer appends every message to Path, or writes it to stdout when Path
+// is empty, instead of delivering it.
+type FileMailer struct {
+	Path string
+	From string
+
+	mu sync.Mutex
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var w io.Writer = os.Stdout
+	if m.Path != "" {
+		f, err := os.OpenFile(m.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if _, err := w.Write(buildMessage(m.From, msg)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewMailer builds the mailer selected by cfg.Driver: "smtp" delivers
+// through the SMTP server, "file" appends messages to cfg.FilePath, and
+// "stdout" (the default) prints them, which is enough for local development.
+func NewMailer(cfg config.MailerConfig) Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return &SMTPMailer{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.From,
+		}
+	case "file":
+		return &FileMailer{Path: cfg.FilePath, From: cfg.From}
+	default:
+		return &FileMailer{From: cfg.From}
+	}
+}
+
+// buildMessage renders msg as a plain text RFC 5322 message.
+func buildMessage(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database/seeds"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/mailer"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/delivery/http"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/delivery/routes"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/auth_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/authorization_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/email_verification_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/health_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/lockout_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/mfa_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/password_reset_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/signup_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/logger"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/metrics"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// App is the composition root: one instance of the application with its own
+// metrics, repositories, use cases and handlers, all built on the database
+// handle it is given. Several can run in one process, e.g. against separate
+// in-memory databases in tests, but they all log through slog.Default and
+// trace through the global OpenTelemetry provider.
+type App struct {
+	Config  *config.Config
+	DB      *gorm.DB
+	Metrics *metrics.Metrics
+
+	JWTUseCase  jwt_usecase.IJWTUseCase
+	UserUseCase user_usecase.IUserUseCase
+
+	roleRepo mysql.IRoleRepository
+	handlers routes.Handlers
+}
+
+// New wires the application on top of db. It instruments db for metrics and
+// tracing, so db must not be shared with another instance; closing it stays
+// up to the caller.
+func New(cfg *config.Config, db *gorm.DB) (*App, error) {
+	// Export HTTP, database and business metrics at /metrics
+	appMetrics := metrics.New()
+	if err := database.Instrument(db, appMetrics); err != nil {
+		return nil, err
+	}
+	if err := database.Trace(db, cfg.Database.Type); err != nil {
+		return nil, err
+	}
+
+	userRepo := mysql.NewUserRepository(db)
+	refreshTokenRepo := mysql.NewRefreshTokenRepository(db)
+	roleRepo := mysql.NewRoleRepository(db)
+
+	healthChecker := health_usecase.NewHealthChecker(cfg.Health.CheckTimeout)
+	healthChecker.Register("database", func(ctx context.Context) error {
+		return database.Ping(ctx, db)
+	})
+
+	jwtUseCase, err := jwt_usecase.NewJWTUseCase(cfg.JWT, mysql.NewRevokedTokenRepository(db))
+	if err != nil {
+		return nil, err
+	}
+	jwtMiddleware := middlewares.NewJWTMiddleware(jwtUseCase)
+	authorizationMiddleware := middlewares.NewAuthorizationMiddleware(authorization_usecase.NewAuthorizationUseCase(roleRepo))
+
+	mfaUseCase := mfa_usecase.NewMFAUseCase(cfg.MFA, userRepo, mysql.NewRecoveryCodeRepository(db))
+	lockoutUseCase := lockout_usecase.NewLockoutUseCase(cfg.Lockout, mysql.NewLoginLockoutRepository(db))
+	authUseCase := auth_usecase.NewAuthUseCase(cfg.Auth, userRepo, refreshTokenRepo, roleRepo, jwtUseCase, mfaUseCase, lockoutUseCase, appMetrics)
+
+	mail := mailer.NewMailer(cfg.Mailer)
+	dispatcher := mailer.NewDispatcher(mail, cfg.Mailer.MaxPending)
+	emailVerificationUseCase := email_verification_usecase.NewEmailVerificationUseCase(cfg.EmailVerification, userRepo, jwtUseCase, dispatcher)
+	userUseCase := user_usecase.NewUserUseCase(cfg.Signup, userRepo, refreshTokenRepo, roleRepo, jwtUseCase, emailVerificationUseCase, appMetrics)
+	signupUseCase := signup_usecase.NewSignupUseCase(cfg.Signup, userUseCase, mysql.NewInvitationRepository(db), mysql.NewTransactor(db), dispatcher)
+	passwordResetUseCase := password_reset_usecase.NewPasswordResetUseCase(cfg.PasswordReset, userRepo, mysql.NewPasswordResetTokenRepository(db), userUseCase, dispatcher)
+
+	return &App{
+		Config:      cfg,
+		DB:          db,
+		Metrics:     appMetrics,
+		JWTUseCase:  jwtUseCase,
+		UserUseCase: userUseCase,
+		roleRepo:    roleRepo,
+		handlers: routes.Handlers{
+			Health:            http.NewHealthHandler(healthChecker),
+			Metrics:           http.NewMetricsHandler(appMetrics),
+			Auth:              http.NewAuthHandler(authUseCase, jwtMiddleware),
+			MFA:               http.NewMFAHandler(mfaUseCase, authUseCase, jwtMiddleware),
+			User:              http.NewUserHandler(userUseCase, jwtMiddleware, authorizationMiddleware),
+			Me:                http.NewMeHandler(userUseCase, jwtMiddleware),
+			Signup:            http.NewSignupHandler(signupUseCase, jwtMiddleware, authorizationMiddleware),
+			PasswordReset:     http.NewPasswordResetHandler(passwordResetUseCase),
+			EmailVerification: http.NewEmailVerificationHandler(emailVerificationUseCase),
+			Lockout:           http.NewLockoutHandler(lockoutUseCase, jwtMiddleware, authorizationMiddleware),
+			JWKS:              http.NewJWKSHandler(jwtUseCase),
+		},
+	}, nil
+}
+
+// NewRouter returns a Gin router serving every route of the application
+// behind the common middlewares.
+func (a *App) NewRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(
+		middlewares.NewRequestIDMiddleware().Middleware(),
+		tracing.Middleware(),
+		a.Metrics.Middleware(),
+		logger.Middleware(slog.Default()),
+		logger.Recovery(),
+		middlewares.NewDBTimeoutMiddleware(a.Config.Database.RequestTimeout).Middleware(),
+	)
+	routes.RegisterRoutes(r, a.handlers)
+	return r
+}
+
+// Seed creates the built-in roles, the bootstrap admin and the fixture users.
+func (a *App) Seed(ctx context.Context) error {
+	return seeds.Seed(ctx, a.Config, a.UserUseCase, a.roleRepo)
+}
@@ -0,0 +1,298 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database/migrations"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	testPassword = "Corr3ct-Horse-Battery"
+	adminEmail   = "admin@example.com"
+)
+
+// testApp serves a fresh application backed by an in-memory SQLite database
+// and a mailer writing to mailPath.
+type testApp struct {
+	router   *gin.Engine
+	mailPath string
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Database.Type = "sqlite"
+	cfg.Database.DSN = ":memory:"
+	cfg.JWT.SecretKey = "test-secret-that-is-long-enough-for-hs256"
+	cfg.Signup.Policy = config.SignupPolicyOpen
+	cfg.Mailer.Driver = "file"
+	cfg.Mailer.FilePath = filepath.Join(t.TempDir(), "mail.txt")
+	cfg.Seed.AdminEmail = adminEmail
+	cfg.Seed.AdminPassword = testPassword
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+
+	migrator, err := migrations.New(db, cfg.Database.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := New(&cfg, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Seed(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testApp{router: a.NewRouter(), mailPath: cfg.Mailer.FilePath}
+}
+
+// do sends a JSON request and decodes the JSON response body.
+func (a *testApp) do(t *testing.T, method, path, token string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, req)
+
+	var decoded map[string]interface{}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("%s %s: response is not JSON: %s", method, path, rec.Body.String())
+		}
+	}
+	return rec.Code, decoded
+}
+
+func (a *testApp) signup(t *testing.T, email string) {
+	t.Helper()
+
+	status, body := a.do(t, http.MethodPost, "/api/v1/auth/signup", "", map[string]string{
+		"first_name": "Test",
+		"last_name":  "User",
+		"email":      email,
+		"password":   testPassword,
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("signup: status %d, body %v", status, body)
+	}
+}
+
+func (a *testApp) login(t *testing.T, email, password string) string {
+	t.Helper()
+
+	status, body := a.do(t, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("login: status %d, body %v", status, body)
+	}
+	token, _ := body["token"].(map[string]interface{})
+	accessToken, _ := token["access_token"].(string)
+	return accessToken
+}
+
+var (
+	verificationLink = regexp.MustCompile(`verify_email\?token=([A-Za-z0-9_.-]+)`)
+	resetLink        = regexp.MustCompile(`reset_password\?token=([A-Za-z0-9_.-]+)`)
+)
+
+// mailedToken waits for a mail sent in the background whose link matches
+// pattern and returns the token of the link.
+func (a *testApp) mailedToken(t *testing.T, pattern *regexp.Regexp) string {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		mail, _ := os.ReadFile(a.mailPath)
+		if match := pattern.FindSubmatch(mail); match != nil {
+			return string(match[1])
+		}
+	}
+	t.Fatalf("no email matching %s was sent", pattern)
+	return ""
+}
+
+func TestVerificationLinkSurvivesPasswordChange(t *testing.T) {
+	a := newTestApp(t)
+	const email = "verify@example.com"
+
+	a.signup(t, email)
+	link := a.mailedToken(t, verificationLink)
+
+	// Changing the password revokes every session of the user
+	accessToken := a.login(t, email, testPassword)
+	const newPassword = "An0ther-Correct-Horse"
+	status, body := a.do(t, http.MethodPut, "/api/v1/me/password", accessToken, map[string]string{
+		"old_password":         testPassword,
+		"new_password":         newPassword,
+		"confirm_new_password": newPassword,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("change password: status %d, body %v", status, body)
+	}
+	if status, _ := a.do(t, http.MethodGet, "/api/v1/me", accessToken, nil); status != http.StatusUnauthorized {
+		t.Fatalf("old session after password change: status %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	status, body = a.do(t, http.MethodGet, "/api/v1/auth/verify_email?token="+link, "", nil)
+	if status != http.StatusOK {
+		t.Fatalf("verify email: status %d, body %v", status, body)
+	}
+	user, _ := body["user"].(map[string]interface{})
+	if user["email_verified_at"] == nil {
+		t.Errorf("user is still unverified: %v", user)
+	}
+}
+
+func TestResetPasswordRequiresMinimumLength(t *testing.T) {
+	a := newTestApp(t)
+	const email = "reset@example.com"
+
+	a.signup(t, email)
+	if status, body := a.do(t, http.MethodPost, "/api/v1/auth/forgot_password", "", map[string]string{"email": email}); status != http.StatusAccepted {
+		t.Fatalf("forgot password: status %d, body %v", status, body)
+	}
+	token := a.mailedToken(t, resetLink)
+
+	reset := func(password string) (int, map[string]interface{}) {
+		return a.do(t, http.MethodPost, "/api/v1/auth/reset_password", "", map[string]string{
+			"token":                token,
+			"new_password":         password,
+			"confirm_new_password": password,
+		})
+	}
+
+	if status, body := reset("x"); status != http.StatusBadRequest {
+		t.Fatalf("reset to a short password: status %d, body %v", status, body)
+	}
+
+	// The refused attempt must leave the link usable
+	const newPassword = "An0ther-Correct-Horse"
+	if status, body := reset(newPassword); status != http.StatusOK {
+		t.Fatalf("reset: status %d, body %v", status, body)
+	}
+	a.login(t, email, newPassword)
+}
+
+func TestResendVerificationIsThrottled(t *testing.T) {
+	a := newTestApp(t)
+	const email = "resend@example.com"
+
+	a.signup(t, email)
+	a.mailedToken(t, verificationLink)
+
+	resend := func(address string) int {
+		status, _ := a.do(t, http.MethodPost, "/api/v1/auth/verify_email/resend", "", map[string]string{"email": address})
+		return status
+	}
+
+	// One resend per address per cooldown
+	for i := 1; i <= 2; i++ {
+		if status := resend(email); status != http.StatusAccepted {
+			t.Fatalf("resend %d: status %d", i, status)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	mail, _ := os.ReadFile(a.mailPath)
+	if sent := strings.Count(string(mail), "Subject: Confirm your email address"); sent != 2 {
+		t.Errorf("%d verification emails sent, want the signup one and one resend", sent)
+	}
+
+	// The default allows 10 requests per client IP in each window
+	for i := 3; i <= 10; i++ {
+		if status := resend(email); status != http.StatusAccepted {
+			t.Fatalf("resend %d: status %d", i, status)
+		}
+	}
+	status, body := a.do(t, http.MethodPost, "/api/v1/auth/verify_email/resend", "", map[string]string{"email": email})
+	if status != http.StatusTooManyRequests {
+		t.Fatalf("resend over the limit: status %d, body %v", status, body)
+	}
+}
+
+func TestErrorResponsesCarryRequestID(t *testing.T) {
+	a := newTestApp(t)
+	const email = "errors@example.com"
+
+	a.signup(t, email)
+	userToken := a.login(t, email, testPassword)
+	adminToken := a.login(t, adminEmail, testPassword)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       interface{}
+		wantStatus int
+	}{
+		{"missing token", http.MethodGet, "/api/v1/me", "", nil, http.StatusUnauthorized},
+		{"malformed token", http.MethodGet, "/api/v1/me", "not-a-jwt", nil, http.StatusUnauthorized},
+		{"missing permission", http.MethodGet, "/api/v1/users/", userToken, nil, http.StatusForbidden},
+		{"unknown user", http.MethodGet, "/api/v1/users/" + uuid.NewString(), adminToken, nil, http.StatusNotFound},
+		{"invalid user id", http.MethodGet, "/api/v1/users/42", adminToken, nil, http.StatusBadRequest},
+		{"wrong password", http.MethodPost, "/api/v1/auth/login", "", map[string]string{"email": email, "password": "Wr0ng-Password!"}, http.StatusUnauthorized},
+		{"invalid login body", http.MethodPost, "/api/v1/auth/login", "", map[string]string{"email": "nope"}, http.StatusBadRequest},
+		{"email taken", http.MethodPatch, "/api/v1/me", userToken, map[string]string{"email": adminEmail}, http.StatusConflict},
+		{"wrong old password", http.MethodPut, "/api/v1/me/password", userToken, map[string]string{"old_password": "Wr0ng-Password!", "new_password": "An0ther-Correct-Horse", "confirm_new_password": "An0ther-Correct-Horse"}, http.StatusBadRequest},
+		{"short new password", http.MethodPut, "/api/v1/me/password", userToken, map[string]string{"old_password": testPassword, "new_password": "x", "confirm_new_password": "x"}, http.StatusBadRequest},
+		{"invalid signup body", http.MethodPost, "/api/v1/auth/signup", "", map[string]string{"email": "nope"}, http.StatusBadRequest},
+		{"invalid forgot password body", http.MethodPost, "/api/v1/auth/forgot_password", "", map[string]string{}, http.StatusBadRequest},
+		{"invalid reset token", http.MethodPost, "/api/v1/auth/reset_password", "", map[string]string{"token": "nope", "new_password": testPassword, "confirm_new_password": testPassword}, http.StatusBadRequest},
+		{"missing verification token", http.MethodGet, "/api/v1/auth/verify_email", "", nil, http.StatusBadRequest},
+		{"invalid verification token", http.MethodGet, "/api/v1/auth/verify_email?token=nope", "", nil, http.StatusBadRequest},
+		{"invalid refresh token", http.MethodPost, "/api/v1/auth/refresh", "", map[string]string{"refresh_token": "nope"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := a.do(t, tt.method, tt.path, tt.token, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status %d, want %d, body %v", status, tt.wantStatus, body)
+			}
+			if message, _ := body["error"].(string); message == "" {
+				t.Errorf("body has no error message: %v", body)
+			}
+			if requestID, _ := body["request_id"].(string); requestID == "" {
+				t.Errorf("body has no request_id: %v", body)
+			}
+		})
+	}
+}
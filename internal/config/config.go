@@ -0,0 +1,350 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Config is the whole application configuration. Every value can be set in
+// the optional config file under the config key path, e.g. jwt.issuer, and
+// overridden by the environment variable named in its env tag.
+type Config struct {
+	Env               string                  `config:"env" env:"APP_ENV"`
+	Log               LogConfig               `config:"log"`
+	Server            ServerConfig            `config:"server"`
+	Database          DatabaseConfig          `config:"database"`
+	JWT               JWTConfig               `config:"jwt"`
+	Auth              AuthConfig              `config:"auth"`
+	MFA               MFAConfig               `config:"mfa"`
+	Lockout           LockoutConfig           `config:"lockout"`
+	Signup            SignupConfig            `config:"signup"`
+	PasswordReset     PasswordResetConfig     `config:"password_reset"`
+	EmailVerification EmailVerificationConfig `config:"email_verification"`
+	Mailer            MailerConfig            `config:"mailer"`
+	Health            HealthConfig            `config:"health"`
+	Tracing           TracingConfig           `config:"tracing"`
+	Seed              SeedConfig              `config:"seed"`
+}
+
+type ServerConfig struct {
+	Addr              string        `config:"addr" env:"SERVER_ADDR"`
+	ReadTimeout       time.Duration `config:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `config:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `config:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `config:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	MaxHeaderBytes    int           `config:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES"`
+	// ShutdownTimeout bounds how long in-flight requests may take to finish
+	// once a shutdown signal arrives.
+	ShutdownTimeout time.Duration `config:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	// TLS is served when both files are set.
+	TLSCertFile string `config:"tls_cert_file" env:"SERVER_TLS_CERT_FILE"`
+	TLSKeyFile  string `config:"tls_key_file" env:"SERVER_TLS_KEY_FILE"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error; debug also logs every SQL query.
+	Level string `config:"level" env:"LOG_LEVEL"`
+	// Format is json or text.
+	Format string `config:"format" env:"LOG_FORMAT"`
+}
+
+type DatabaseConfig struct {
+	// Type is mysql, postgres or sqlite. A sqlite DSN is a file path, or
+	// :memory: for a database that lives as long as the process.
+	Type               string        `config:"type" env:"DB_TYPE"`
+	DSN                string        `config:"dsn" env:"DB_DSN"`
+	SlowQueryThreshold time.Duration `config:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD"`
+	// RequestTimeout bounds the database work of a single request; 0 disables it.
+	RequestTimeout time.Duration `config:"request_timeout" env:"DB_REQUEST_TIMEOUT"`
+	// AutoMigrate applies pending migrations when the server starts.
+	AutoMigrate bool `config:"auto_migrate" env:"DB_AUTO_MIGRATE"`
+	// AutoSeed creates the built-in roles and the admin user when the server starts.
+	AutoSeed bool `config:"auto_seed" env:"DB_AUTO_SEED"`
+}
+
+type JWTConfig struct {
+	SigningAlg     string `config:"signing_alg" env:"JWT_SIGNING_ALG"`
+	KeyID          string `config:"key_id" env:"JWT_KEY_ID"`
+	SecretKey      string `config:"secret_key" env:"JWT_SECRET_KEY"`
+	SecretKeyFile  string `config:"secret_key_file" env:"JWT_SECRET_KEY_FILE"`
+	PrivateKeyFile string `config:"private_key_file" env:"JWT_PRIVATE_KEY_FILE"`
+	// VerificationKeys lists PEM files of keys still trusted after a
+	// rotation, optionally as "kid=path".
+	VerificationKeys []string `config:"verification_keys" env:"JWT_VERIFICATION_KEYS"`
+	// PreviousSecretKeys lists retired HMAC secrets as "kid=secret".
+	PreviousSecretKeys []string      `config:"previous_secret_keys" env:"JWT_PREVIOUS_SECRET_KEYS"`
+	Issuer             string        `config:"issuer" env:"JWT_ISSUER"`
+	Audience           []string      `config:"audience" env:"JWT_AUDIENCE"`
+	Leeway             time.Duration `config:"leeway" env:"JWT_LEEWAY"`
+	AccessTokenTTL     time.Duration `config:"access_token_ttl" env:"JWT_ACCESS_TOKEN_TTL"`
+}
+
+type AuthConfig struct {
+	RefreshTokenTTL          time.Duration `config:"refresh_token_ttl" env:"JWT_REFRESH_TOKEN_TTL"`
+	RequireEmailVerification bool          `config:"require_email_verification" env:"REQUIRE_EMAIL_VERIFICATION"`
+}
+
+type MFAConfig struct {
+	// Issuer is shown in authenticator apps and defaults to the JWT issuer.
+	Issuer string `config:"issuer" env:"MFA_ISSUER"`
+}
+
+type LockoutConfig struct {
+	MaxFailedAttempts      int           `config:"max_failed_attempts" env:"LOGIN_MAX_FAILED_ATTEMPTS"`
+	MaxFailedAttemptsPerIP int           `config:"max_failed_attempts_per_ip" env:"LOGIN_MAX_FAILED_ATTEMPTS_PER_IP"`
+	Base                   time.Duration `config:"base" env:"LOGIN_LOCKOUT_BASE"`
+	Max                    time.Duration `config:"max" env:"LOGIN_LOCKOUT_MAX"`
+	FailureWindow          time.Duration `config:"failure_window" env:"LOGIN_FAILURE_WINDOW"`
+}
+
+const (
+	SignupPolicyDisabled   = "disabled"
+	SignupPolicyOpen       = "open"
+	SignupPolicyInviteOnly = "invite"
+	SignupPolicyDomain     = "domain"
+)
+
+type SignupConfig struct {
+	Policy         string        `config:"policy" env:"SIGNUP_POLICY"`
+	AllowedDomains []string      `config:"allowed_domains" env:"SIGNUP_ALLOWED_DOMAINS"`
+	URL            string        `config:"url" env:"SIGNUP_URL"`
+	InvitationTTL  time.Duration `config:"invitation_ttl" env:"SIGNUP_INVITATION_TTL"`
+	MaxPerIP       int           `config:"max_per_ip" env:"SIGNUP_MAX_PER_IP"`
+	ThrottleWindow time.Duration `config:"throttle_window" env:"SIGNUP_THROTTLE_WINDOW"`
+}
+
+type PasswordResetConfig struct {
+	URL      string        `config:"url" env:"PASSWORD_RESET_URL"`
+	TokenTTL time.Duration `config:"token_ttl" env:"PASSWORD_RESET_TOKEN_TTL"`
+	// Cooldown is the minimum time between two reset emails to one address.
+	Cooldown       time.Duration `config:"cooldown" env:"PASSWORD_RESET_COOLDOWN"`
+	MaxPerIP       int           `config:"max_per_ip" env:"PASSWORD_RESET_MAX_PER_IP"`
+	ThrottleWindow time.Duration `config:"throttle_window" env:"PASSWORD_RESET_THROTTLE_WINDOW"`
+}
+
+type EmailVerificationConfig struct {
+	URL      string        `config:"url" env:"EMAIL_VERIFICATION_URL"`
+	TokenTTL time.Duration `config:"token_ttl" env:"EMAIL_VERIFICATION_TOKEN_TTL"`
+	// Cooldown is the minimum time between two resent links to one address.
+	Cooldown       time.Duration `config:"cooldown" env:"EMAIL_VERIFICATION_COOLDOWN"`
+	MaxPerIP       int           `config:"max_per_ip" env:"EMAIL_VERIFICATION_MAX_PER_IP"`
+	ThrottleWindow time.Duration `config:"throttle_window" env:"EMAIL_VERIFICATION_THROTTLE_WINDOW"`
+}
+
+type MailerConfig struct {
+	// Driver is smtp, file or stdout.
+	Driver   string     `config:"driver" env:"MAILER_DRIVER"`
+	From     string     `config:"from" env:"MAIL_FROM"`
+	FilePath string     `config:"file_path" env:"MAILER_FILE_PATH"`
+	SMTP     SMTPConfig `config:"smtp"`
+	// MaxPending bounds the emails being sent in the background at once.
+	MaxPending int `config:"max_pending" env:"MAILER_MAX_PENDING"`
+}
+
+type SMTPConfig struct {
+	Host     string `config:"host" env:"SMTP_HOST"`
+	Port     string `config:"port" env:"SMTP_PORT"`
+	Username string `config:"username" env:"SMTP_USERNAME"`
+	Password string `config:"password" env:"SMTP_PASSWORD"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout bounds each readiness check.
+	CheckTimeout time.Duration `config:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+}
+
+type TracingConfig struct {
+	// Exporter is none, otlp, stdout or file.
+	Exporter string `config:"exporter" env:"TRACING_EXPORTER"`
+	// OTLPEndpoint is the collector URL; the standard OTEL_EXPORTER_OTLP_*
+	// variables apply when it is empty.
+	OTLPEndpoint string  `config:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	FilePath     string  `config:"file_path" env:"TRACING_FILE_PATH"`
+	ServiceName  string  `config:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio  float64 `config:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type SeedConfig struct {
+	// AdminEmail is the bootstrap admin account; no admin is seeded when empty.
+	AdminEmail     string `config:"admin_email" env:"SEED_ADMIN_EMAIL"`
+	AdminFirstName string `config:"admin_first_name" env:"SEED_ADMIN_FIRST_NAME"`
+	AdminLastName  string `config:"admin_last_name" env:"SEED_ADMIN_LAST_NAME"`
+	// AdminPasswordFile takes precedence over AdminPassword, e.g. for a
+	// mounted secret.
+	AdminPassword     string `config:"admin_password" env:"SEED_ADMIN_PASSWORD"`
+	AdminPasswordFile string `config:"admin_password_file" env:"SEED_ADMIN_PASSWORD_FILE"`
+	// FixturesDir holds YAML or JSON fixture files; files in its subdirectory
+	// named after APP_ENV are loaded after the shared ones.
+	FixturesDir string `config:"fixtures_dir" env:"SEED_FIXTURES_DIR"`
+}
+
+// Default returns the configuration used for every value left unset.
+func Default() Config {
+	return Config{
+		Env: "production",
+		Log: LogConfig{Level: "info", Format: "text"},
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			MaxHeaderBytes:    1 << 20,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			SlowQueryThreshold: 200 * time.Millisecond,
+			RequestTimeout:     5 * time.Second,
+		},
+		JWT: JWTConfig{
+			SigningAlg:     "HS256",
+			Leeway:         30 * time.Second,
+			AccessTokenTTL: 15 * time.Minute,
+		},
+		Auth: AuthConfig{RefreshTokenTTL: 30 * 24 * time.Hour},
+		Lockout: LockoutConfig{
+			MaxFailedAttempts:      5,
+			MaxFailedAttemptsPerIP: 20,
+			Base:                   time.Minute,
+			Max:                    time.Hour,
+			FailureWindow:          15 * time.Minute,
+		},
+		Signup: SignupConfig{
+			Policy:         SignupPolicyDisabled,
+			URL:            "http://localhost:8080/signup",
+			InvitationTTL:  7 * 24 * time.Hour,
+			MaxPerIP:       5,
+			ThrottleWindow: time.Hour,
+		},
+		PasswordReset: PasswordResetConfig{
+			URL:            "http://localhost:8080/reset_password",
+			TokenTTL:       time.Hour,
+			Cooldown:       time.Minute,
+			MaxPerIP:       10,
+			ThrottleWindow: time.Hour,
+		},
+		EmailVerification: EmailVerificationConfig{
+			URL:            "http://localhost:8080/api/v1/auth/verify_email",
+			TokenTTL:       24 * time.Hour,
+			Cooldown:       time.Minute,
+			MaxPerIP:       10,
+			ThrottleWindow: time.Hour,
+		},
+		Mailer: MailerConfig{
+			Driver:     "stdout",
+			From:       "no-reply@localhost",
+			MaxPending: 16,
+		},
+		Health: HealthConfig{CheckTimeout: 2 * time.Second},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "golang-clean-architecture",
+			SampleRatio: 1,
+		},
+		Seed: SeedConfig{
+			AdminFirstName: "User",
+			AdminLastName:  "Admin",
+		},
+	}
+}
+
+// TLSEnabled reports whether the server must serve HTTPS.
+func (c ServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// Validate reports every missing or inconsistent value at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	check(c.Log.Format == "json" || c.Log.Format == "text", "LOG_FORMAT must be json or text, got %q", c.Log.Format)
+
+	check(c.Server.Addr != "", "SERVER_ADDR is required")
+	check(c.Server.ReadTimeout >= 0 && c.Server.ReadHeaderTimeout >= 0 && c.Server.WriteTimeout >= 0 && c.Server.IdleTimeout >= 0,
+		"SERVER_*_TIMEOUT values cannot be negative")
+	check(c.Server.MaxHeaderBytes > 0, "SERVER_MAX_HEADER_BYTES must be positive")
+	check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT must be positive")
+	check((c.Server.TLSCertFile == "") == (c.Server.TLSKeyFile == ""), "SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set together")
+
+	check(c.Database.Type == "mysql" || c.Database.Type == "postgres" || c.Database.Type == "sqlite", "DB_TYPE must be mysql, postgres or sqlite, got %q", c.Database.Type)
+	check(c.Database.DSN != "", "DB_DSN is required")
+	check(c.Database.SlowQueryThreshold >= 0, "DB_SLOW_QUERY_THRESHOLD cannot be negative")
+	check(c.Database.RequestTimeout >= 0, "DB_REQUEST_TIMEOUT cannot be negative")
+
+	if strings.HasPrefix(c.JWT.SigningAlg, "HS") {
+		check(c.JWT.SecretKey != "" || c.JWT.SecretKeyFile != "", "JWT_SECRET_KEY or JWT_SECRET_KEY_FILE is required for %s", c.JWT.SigningAlg)
+	} else {
+		check(c.JWT.PrivateKeyFile != "", "JWT_PRIVATE_KEY_FILE is required for %s", c.JWT.SigningAlg)
+	}
+	check(c.JWT.AccessTokenTTL > 0, "JWT_ACCESS_TOKEN_TTL must be positive")
+	check(c.JWT.Leeway >= 0, "JWT_LEEWAY cannot be negative")
+	check(c.Auth.RefreshTokenTTL > 0, "JWT_REFRESH_TOKEN_TTL must be positive")
+
+	check(c.Lockout.MaxFailedAttempts > 0, "LOGIN_MAX_FAILED_ATTEMPTS must be positive")
+	check(c.Lockout.MaxFailedAttemptsPerIP > 0, "LOGIN_MAX_FAILED_ATTEMPTS_PER_IP must be positive")
+	check(c.Lockout.Base > 0 && c.Lockout.Max >= c.Lockout.Base, "LOGIN_LOCKOUT_BASE must be positive and at most LOGIN_LOCKOUT_MAX")
+	check(c.Lockout.FailureWindow > 0, "LOGIN_FAILURE_WINDOW must be positive")
+
+	switch c.Signup.Policy {
+	case SignupPolicyDisabled, SignupPolicyOpen, SignupPolicyInviteOnly:
+	case SignupPolicyDomain:
+		check(len(c.Signup.AllowedDomains) > 0, "SIGNUP_ALLOWED_DOMAINS is required when SIGNUP_POLICY is domain")
+	default:
+		check(false, "SIGNUP_POLICY must be disabled, open, invite or domain, got %q", c.Signup.Policy)
+	}
+	check(c.Signup.InvitationTTL > 0, "SIGNUP_INVITATION_TTL must be positive")
+	check(c.Signup.MaxPerIP > 0, "SIGNUP_MAX_PER_IP must be positive")
+	check(c.Signup.ThrottleWindow > 0, "SIGNUP_THROTTLE_WINDOW must be positive")
+
+	check(c.PasswordReset.URL != "", "PASSWORD_RESET_URL is required")
+	check(c.PasswordReset.TokenTTL > 0, "PASSWORD_RESET_TOKEN_TTL must be positive")
+	check(c.PasswordReset.Cooldown >= 0, "PASSWORD_RESET_COOLDOWN cannot be negative")
+	check(c.PasswordReset.MaxPerIP > 0, "PASSWORD_RESET_MAX_PER_IP must be positive")
+	check(c.PasswordReset.ThrottleWindow > 0, "PASSWORD_RESET_THROTTLE_WINDOW must be positive")
+	check(c.EmailVerification.URL != "", "EMAIL_VERIFICATION_URL is required")
+	check(c.EmailVerification.TokenTTL > 0, "EMAIL_VERIFICATION_TOKEN_TTL must be positive")
+	check(c.EmailVerification.Cooldown >= 0, "EMAIL_VERIFICATION_COOLDOWN cannot be negative")
+	check(c.EmailVerification.MaxPerIP > 0, "EMAIL_VERIFICATION_MAX_PER_IP must be positive")
+	check(c.EmailVerification.ThrottleWindow > 0, "EMAIL_VERIFICATION_THROTTLE_WINDOW must be positive")
+
+	switch c.Mailer.Driver {
+	case "stdout":
+	case "file":
+		check(c.Mailer.FilePath != "", "MAILER_FILE_PATH is required for the file mailer")
+	case "smtp":
+		check(c.Mailer.SMTP.Host != "", "SMTP_HOST is required for the smtp mailer")
+	default:
+		check(false, "MAILER_DRIVER must be smtp, file or stdout, got %q", c.Mailer.Driver)
+	}
+	check(c.Mailer.MaxPending > 0, "MAILER_MAX_PENDING must be positive")
+
+	check(c.Health.CheckTimeout > 0, "HEALTH_CHECK_TIMEOUT must be positive")
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		check(c.Tracing.FilePath != "", "TRACING_FILE_PATH is required for the file exporter")
+	default:
+		check(false, "TRACING_EXPORTER must be none, otlp, stdout or file, got %q", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+
+	if c.Seed.AdminEmail != "" {
+		check(c.Seed.AdminPassword != "" || c.Seed.AdminPasswordFile != "", "SEED_ADMIN_PASSWORD or SEED_ADMIN_PASSWORD_FILE is required with SEED_ADMIN_EMAIL")
+		check(c.Seed.AdminFirstName != "" && c.Seed.AdminLastName != "", "SEED_ADMIN_FIRST_NAME and SEED_ADMIN_LAST_NAME cannot be empty")
+	}
+
+	return errors.Join(errs...)
+}
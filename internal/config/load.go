@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load builds the configuration from, in increasing precedence, the defaults,
+// the YAML or TOML file named by CONFIG_FILE and the environment, which
+// includes a .env file in the working directory. The result is validated.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
+
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(&cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := visit(reflect.ValueOf(&cfg).Elem(), nil, applyEnv); err != nil {
+		return nil, err
+	}
+
+	if cfg.MFA.Issuer == "" {
+		cfg.MFA.Issuer = cfg.JWT.Issuer
+	}
+	cfg.Signup.Policy = strings.ToLower(cfg.Signup.Policy)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return &cfg, nil
+}
+
+// IsDevelopment reports whether the application runs in a local development
+// environment. It has to be named explicitly, so a missing APP_ENV never
+// relaxes a check.
+func (c *Config) IsDevelopment() bool {
+	return c.Env == "development" || c.Env == "dev"
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return visit(reflect.ValueOf(cfg).Elem(), nil, func(field reflect.Value, _ reflect.StructField, path []string) error {
+		value, ok := lookupPath(values, path)
+		if !ok {
+			return nil
+		}
+		if err := setValue(field, value); err != nil {
+			return fmt.Errorf("config file key %s: %w", strings.Join(path, "."), err)
+		}
+		return nil
+	})
+}
+
+func applyEnv(field reflect.Value, structField reflect.StructField, _ []string) error {
+	key := structField.Tag.Get("env")
+	if key == "" {
+		return nil
+	}
+
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	if err := setValue(field, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// visit calls fn for every leaf field of v with the config key path leading
+// to it.
+func visit(v reflect.Value, path []string, fn func(reflect.Value, reflect.StructField, []string) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := v.Type().Field(i)
+		fieldPath := append(append([]string(nil), path...), structField.Tag.Get("config"))
+
+		if field.Kind() == reflect.Struct {
+			if err := visit(field, fieldPath, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(field, structField, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lookupPath(values map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = values
+	for _, key := range path {
+		section, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = section[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setValue assigns value, a string from the environment or any scalar or
+// list from a config file, to field.
+func setValue(field reflect.Value, value interface{}) error {
+	if field.Kind() == reflect.Slice {
+		var items []string
+		switch list := value.(type) {
+		case []interface{}:
+			for _, item := range list {
+				items = append(items, strings.TrimSpace(fmt.Sprint(item)))
+			}
+		default:
+			for _, item := range strings.Split(fmt.Sprint(value), ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+		return nil
+	}
+
+	text := strings.TrimSpace(fmt.Sprint(value))
+
+	switch {
+	case field.Type() == durationType:
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(text)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Type())
+	}
+
+	return nil
+}
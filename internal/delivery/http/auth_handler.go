@@ -1,26 +1,36 @@
 package http
 
 import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/auth_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/lockout_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type IAuthHandler interface {
 	RegisterRoutes(r *gin.Engine)
 	Login(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
 }
 
 type authHandler struct {
-	authUseCase auth_usecase.IAuthUseCase
-	validator   *validator.Validate
+	authUseCase   auth_usecase.IAuthUseCase
+	jwtMiddleware middlewares.IJWTMiddleware
+	validator     *validator.Validate
 }
 
-func NewAuthHandler(authUseCase auth_usecase.IAuthUseCase) IAuthHandler {
+func NewAuthHandler(authUseCase auth_usecase.IAuthUseCase, middleware middlewares.IJWTMiddleware) IAuthHandler {
 	return &authHandler{
-		authUseCase: authUseCase,
-		validator:   validator.New(),
+		authUseCase:   authUseCase,
+		jwtMiddleware: middleware,
+		validator:     validator.New(),
 	}
 }
 
@@ -28,6 +38,8 @@ func (h *authHandler) RegisterRoutes(r *gin.Engine) {
 	userGroup := r.Group("/api/v1/auth")
 	{
 		userGroup.POST("/login", h.Login)
+		userGroup.POST("/refresh", h.Refresh)
+		userGroup.POST("/logout", h.jwtMiddleware.Middleware(), h.Logout)
 	}
 }
 
@@ -38,20 +50,117 @@ func (h *authHandler) Login(c *gin.Context) {
 	}
 
 	if err := c.ShouldBind(&credentials); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "token": nil})
+		c.JSON(http.StatusBadRequest, loginError(c, err.Error()))
 		return
 	}
 
 	if err := h.validator.Struct(credentials); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "token": nil})
+		c.JSON(http.StatusBadRequest, loginError(c, err.Error()))
 		return
 	}
 
-	token, err := h.authUseCase.Login(credentials.Email, credentials.Password)
+	token, err := h.authUseCase.Login(c.Request.Context(), credentials.Email, credentials.Password, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "token": nil})
+		var lockedErr *lockout_usecase.LockedError
+		if errors.As(err, &lockedErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+
+			status := http.StatusTooManyRequests
+			if errors.Is(err, lockout_usecase.ErrAccountLocked) {
+				status = http.StatusLocked
+			}
+			c.JSON(status, loginError(c, err.Error()))
+			return
+		}
+
+		if errors.Is(err, auth_usecase.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, loginError(c, err.Error()))
+			return
+		}
+
+		if errors.Is(err, auth_usecase.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, loginError(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, loginError(c, err.Error()))
+		return
+	}
+
+	if token.MFARequired {
+		c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication required", "token": token})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token})
 }
+
+func (h *authHandler) Refresh(c *gin.Context) {
+	var refreshRequest struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+
+	if err := c.ShouldBind(&refreshRequest); err != nil {
+		c.JSON(http.StatusBadRequest, loginError(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(refreshRequest); err != nil {
+		c.JSON(http.StatusBadRequest, loginError(c, err.Error()))
+		return
+	}
+
+	token, err := h.authUseCase.Refresh(c.Request.Context(), refreshRequest.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth_usecase.ErrInvalidRefreshToken) || errors.Is(err, auth_usecase.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, loginError(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, loginError(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token refreshed", "token": token})
+}
+
+func (h *authHandler) Logout(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	tokenID, tokenExpiresAt, err := helpers.GetTokenIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	// The refresh token is optional; without it only the access token is revoked.
+	var logoutRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBind(&logoutRequest); err != nil {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+	}
+
+	if err := h.authUseCase.Logout(c.Request.Context(), userID, tokenID, tokenExpiresAt, logoutRequest.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
+}
+
+// loginError is the body of a failed login or refresh, which keeps the empty
+// token field clients read on success.
+func loginError(c *gin.Context, message string) gin.H {
+	body := helpers.ErrorResponse(c, message)
+	body["token"] = nil
+	return body
+}
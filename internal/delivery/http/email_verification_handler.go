@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/email_verification_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type IEmailVerificationHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	VerifyEmail(c *gin.Context)
+	ResendVerification(c *gin.Context)
+}
+
+type emailVerificationHandler struct {
+	emailVerificationUseCase email_verification_usecase.IEmailVerificationUseCase
+	validator                *validator.Validate
+}
+
+func NewEmailVerificationHandler(emailVerificationUseCase email_verification_usecase.IEmailVerificationUseCase) IEmailVerificationHandler {
+	return &emailVerificationHandler{
+		emailVerificationUseCase: emailVerificationUseCase,
+		validator:                validator.New(),
+	}
+}
+
+func (h *emailVerificationHandler) RegisterRoutes(r *gin.Engine) {
+	authGroup := r.Group("/api/v1/auth")
+	{
+		authGroup.GET("/verify_email", h.VerifyEmail)
+		authGroup.POST("/verify_email/resend", h.ResendVerification)
+	}
+}
+
+func (h *emailVerificationHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, "token is required"))
+		return
+	}
+
+	user, err := h.emailVerificationUseCase.Verify(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, email_verification_usecase.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified", "user": user})
+}
+
+func (h *emailVerificationHandler) ResendVerification(c *gin.Context) {
+	var resendRequest struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	if err := c.ShouldBind(&resendRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&resendRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.emailVerificationUseCase.Resend(c.Request.Context(), resendRequest.Email, c.ClientIP()); err != nil {
+		var throttledErr *email_verification_usecase.ThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the email is registered and unverified, a verification link has been sent"})
+}
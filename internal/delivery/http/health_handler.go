@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/health_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IHealthHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	Liveness(c *gin.Context)
+	Readiness(c *gin.Context)
+}
+
+type healthHandler struct {
+	healthChecker health_usecase.IHealthChecker
+}
+
+func NewHealthHandler(healthChecker health_usecase.IHealthChecker) IHealthHandler {
+	return &healthHandler{healthChecker: healthChecker}
+}
+
+func (h *healthHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/healthz", h.Liveness)
+	r.GET("/readyz", h.Readiness)
+}
+
+// Liveness only proves the process serves requests; it never checks
+// dependencies, so a database outage doesn't get the process restarted.
+func (h *healthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, types.HealthReport{Status: types.HealthStatusOK})
+}
+
+func (h *healthHandler) Readiness(c *gin.Context) {
+	report := h.healthChecker.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != types.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IJWKSHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	JWKS(c *gin.Context)
+}
+
+type jwksHandler struct {
+	jwtUseCase jwt_usecase.IJWTUseCase
+}
+
+func NewJWKSHandler(jwtUseCase jwt_usecase.IJWTUseCase) IJWKSHandler {
+	return &jwksHandler{jwtUseCase: jwtUseCase}
+}
+
+func (h *jwksHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+func (h *jwksHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtUseCase.JWKS())
+}
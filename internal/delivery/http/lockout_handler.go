@@ -0,0 +1,109 @@
+package http
+
+import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/lockout_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type ILockoutHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	GetActiveLockouts(c *gin.Context)
+	GetLockout(c *gin.Context)
+	ClearLockout(c *gin.Context)
+}
+
+type lockoutHandler struct {
+	lockoutUseCase          lockout_usecase.ILockoutUseCase
+	jwtMiddleware           middlewares.IJWTMiddleware
+	authorizationMiddleware middlewares.IAuthorizationMiddleware
+}
+
+func NewLockoutHandler(lockoutUseCase lockout_usecase.ILockoutUseCase, middleware middlewares.IJWTMiddleware, authorizationMiddleware middlewares.IAuthorizationMiddleware) ILockoutHandler {
+	return &lockoutHandler{
+		lockoutUseCase:          lockoutUseCase,
+		jwtMiddleware:           middleware,
+		authorizationMiddleware: authorizationMiddleware,
+	}
+}
+
+func (h *lockoutHandler) RegisterRoutes(r *gin.Engine) {
+	lockoutGroup := r.Group("/api/v1/admin/lockouts")
+	lockoutGroup.Use(h.jwtMiddleware.Middleware(), h.authorizationMiddleware.RequirePermission(domain.PermissionLockoutsManage))
+	{
+		lockoutGroup.GET("", h.GetActiveLockouts)
+		lockoutGroup.GET("/:scope/:subject", h.GetLockout)
+		lockoutGroup.DELETE("/:scope/:subject", h.ClearLockout)
+	}
+}
+
+func (h *lockoutHandler) GetActiveLockouts(c *gin.Context) {
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	if offset < 0 {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, "offset cannot be negative"))
+		return
+	}
+
+	if limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, "limit cannot be negative, zero or greater than 100"))
+		return
+	}
+
+	lockouts, total, err := h.lockoutUseCase.GetActiveLockouts(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	response := types.LockoutPaginationResponse{
+		Data:        lockouts,
+		Total:       total,
+		PageSize:    limit,
+		CurrentPage: (offset / limit) + 1,
+		TotalPages:  int((total + int64(limit) - 1) / int64(limit)),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *lockoutHandler) GetLockout(c *gin.Context) {
+	lockout, err := h.lockoutUseCase.GetLockout(c.Request.Context(), c.Param("scope"), c.Param("subject"))
+	if err != nil {
+		if errors.Is(err, lockout_usecase.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if lockout == nil {
+		c.JSON(http.StatusNotFound, helpers.ErrorResponse(c, "no failed logins recorded"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lockout": lockout})
+}
+
+func (h *lockoutHandler) ClearLockout(c *gin.Context) {
+	if err := h.lockoutUseCase.ClearLockout(c.Request.Context(), c.Param("scope"), c.Param("subject")); err != nil {
+		if errors.Is(err, lockout_usecase.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lockout cleared successfully"})
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type IMeHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	GetMe(c *gin.Context)
+	UpdateMe(c *gin.Context)
+	DeleteMe(c *gin.Context)
+	ChangePassword(c *gin.Context)
+}
+
+// meHandler serves the authenticated user's own account, identified by the
+// access token rather than by a path parameter.
+type meHandler struct {
+	userUseCase   user_usecase.IUserUseCase
+	jwtMiddleware middlewares.IJWTMiddleware
+	validator     *validator.Validate
+}
+
+func NewMeHandler(us user_usecase.IUserUseCase, middleware middlewares.IJWTMiddleware) IMeHandler {
+	return &meHandler{
+		userUseCase:   us,
+		jwtMiddleware: middleware,
+		validator:     validator.New(),
+	}
+}
+
+func (h *meHandler) RegisterRoutes(r *gin.Engine) {
+	meGroup := r.Group("/api/v1/me", h.jwtMiddleware.Middleware())
+	{
+		meGroup.GET("", h.GetMe)
+		meGroup.PATCH("", h.UpdateMe)
+		meGroup.DELETE("", h.DeleteMe)
+		meGroup.PUT("/password", h.ChangePassword)
+	}
+}
+
+func (h *meHandler) GetMe(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	user, err := h.userUseCase.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, helpers.ErrorResponse(c, "User not found"))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Get user", "user": user})
+}
+
+// UpdateMe changes only the fields present in the body. The full name follows
+// first and last name unless it is sent explicitly.
+func (h *meHandler) UpdateMe(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var updateRequest struct {
+		FirstName *string `json:"first_name" validate:"omitempty,min=1,max=155"`
+		LastName  *string `json:"last_name" validate:"omitempty,min=1,max=155"`
+		FullName  *string `json:"full_name" validate:"omitempty,max=310"`
+		Email     *string `json:"email" validate:"omitempty,email"`
+	}
+
+	if err := c.ShouldBind(&updateRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&updateRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	user, err := h.userUseCase.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, helpers.ErrorResponse(c, "User not found"))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if updateRequest.FirstName != nil || updateRequest.LastName != nil {
+		user.FullName = ""
+	}
+	if updateRequest.FirstName != nil {
+		user.FirstName = *updateRequest.FirstName
+	}
+	if updateRequest.LastName != nil {
+		user.LastName = *updateRequest.LastName
+	}
+	if updateRequest.FullName != nil {
+		user.FullName = *updateRequest.FullName
+	}
+	if updateRequest.Email != nil {
+		user.Email = *updateRequest.Email
+	}
+
+	if err := h.userUseCase.UpdateUser(c.Request.Context(), user); err != nil {
+		if errors.Is(err, user_usecase.ErrEmailAlreadyRegistered) {
+			c.JSON(http.StatusConflict, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+		if errors.Is(err, user_usecase.ErrEmailDomainNotAllowed) {
+			c.JSON(http.StatusForbidden, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully", "user": user})
+}
+
+func (h *meHandler) DeleteMe(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	if err := h.userUseCase.DeleteUser(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Delete user"})
+}
+
+func (h *meHandler) ChangePassword(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	if err := helpers.IsValidUUIDv4(userID); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	var changePasswordRequest struct {
+		OldPassword        string `json:"old_password" validate:"required"`
+		NewPassword        string `json:"new_password" validate:"required,min=8"`
+		ConfirmNewPassword string `json:"confirm_new_password" validate:"required,eqfield=NewPassword"`
+	}
+
+	if err := c.ShouldBind(&changePasswordRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&changePasswordRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.userUseCase.ResetPassword(c.Request.Context(), userID, changePasswordRequest.OldPassword, changePasswordRequest.NewPassword); err != nil {
+		if errors.Is(err, user_usecase.ErrInvalidOldPassword) {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
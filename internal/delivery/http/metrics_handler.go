@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+type IMetricsHandler interface {
+	RegisterRoutes(r *gin.Engine)
+}
+
+type metricsHandler struct {
+	metrics *metrics.Metrics
+}
+
+func NewMetricsHandler(appMetrics *metrics.Metrics) IMetricsHandler {
+	return &metricsHandler{metrics: appMetrics}
+}
+
+func (h *metricsHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/metrics", gin.WrapH(h.metrics.Handler()))
+}
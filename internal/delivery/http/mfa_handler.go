@@ -0,0 +1,162 @@
+package http
+
+import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/auth_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/mfa_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type IMFAHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	Enroll(c *gin.Context)
+	Confirm(c *gin.Context)
+	Disable(c *gin.Context)
+	Verify(c *gin.Context)
+}
+
+type mfaHandler struct {
+	mfaUseCase    mfa_usecase.IMFAUseCase
+	authUseCase   auth_usecase.IAuthUseCase
+	jwtMiddleware middlewares.IJWTMiddleware
+	validator     *validator.Validate
+}
+
+func NewMFAHandler(mfaUseCase mfa_usecase.IMFAUseCase, authUseCase auth_usecase.IAuthUseCase, middleware middlewares.IJWTMiddleware) IMFAHandler {
+	return &mfaHandler{
+		mfaUseCase:    mfaUseCase,
+		authUseCase:   authUseCase,
+		jwtMiddleware: middleware,
+		validator:     validator.New(),
+	}
+}
+
+func (h *mfaHandler) RegisterRoutes(r *gin.Engine) {
+	mfaGroup := r.Group("/api/v1/auth/mfa")
+	{
+		mfaGroup.POST("/verify", h.Verify)
+		mfaGroup.POST("/enroll", h.jwtMiddleware.Middleware(), h.Enroll)
+		mfaGroup.POST("/confirm", h.jwtMiddleware.Middleware(), h.Confirm)
+		mfaGroup.POST("/disable", h.jwtMiddleware.Middleware(), h.Disable)
+	}
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func (h *mfaHandler) Enroll(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	enrollment, err := h.mfaUseCase.Enroll(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, mfa_usecase.ErrMFAAlreadyEnabled) {
+			c.JSON(http.StatusConflict, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scan the URI with an authenticator app and confirm with a code", "mfa": enrollment})
+}
+
+func (h *mfaHandler) Confirm(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var request mfaCodeRequest
+	if !h.bind(c, &request) {
+		return
+	}
+
+	recoveryCodes, err := h.mfaUseCase.ConfirmEnrollment(c.Request.Context(), userID, request.Code)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication enabled", "mfa": recoveryCodes})
+}
+
+func (h *mfaHandler) Disable(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var request mfaCodeRequest
+	if !h.bind(c, &request) {
+		return
+	}
+
+	if err := h.mfaUseCase.Disable(c.Request.Context(), userID, request.Code); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func (h *mfaHandler) Verify(c *gin.Context) {
+	var request struct {
+		MFAToken string `json:"mfa_token" validate:"required"`
+		Code     string `json:"code" validate:"required"`
+	}
+	if !h.bind(c, &request) {
+		return
+	}
+
+	token, err := h.authUseCase.VerifyMFA(c.Request.Context(), request.MFAToken, request.Code)
+	if err != nil {
+		if errors.Is(err, auth_usecase.ErrInvalidMFAChallenge) || errors.Is(err, mfa_usecase.ErrInvalidMFACode) {
+			c.JSON(http.StatusUnauthorized, loginError(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, loginError(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token})
+}
+
+func (h *mfaHandler) bind(c *gin.Context, request interface{}) bool {
+	if err := c.ShouldBind(request); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return false
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return false
+	}
+
+	return true
+}
+
+func (h *mfaHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, mfa_usecase.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, err.Error()))
+	case errors.Is(err, mfa_usecase.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, helpers.ErrorResponse(c, err.Error()))
+	case errors.Is(err, mfa_usecase.ErrMFANotEnrolled), errors.Is(err, mfa_usecase.ErrMFANotEnabled):
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+	}
+}
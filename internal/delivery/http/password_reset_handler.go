@@ -0,0 +1,98 @@
+package http
+
+import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/password_reset_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type IPasswordResetHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+}
+
+type passwordResetHandler struct {
+	passwordResetUseCase password_reset_usecase.IPasswordResetUseCase
+	validator            *validator.Validate
+}
+
+func NewPasswordResetHandler(passwordResetUseCase password_reset_usecase.IPasswordResetUseCase) IPasswordResetHandler {
+	return &passwordResetHandler{
+		passwordResetUseCase: passwordResetUseCase,
+		validator:            validator.New(),
+	}
+}
+
+func (h *passwordResetHandler) RegisterRoutes(r *gin.Engine) {
+	authGroup := r.Group("/api/v1/auth")
+	{
+		authGroup.POST("/forgot_password", h.ForgotPassword)
+		authGroup.POST("/reset_password", h.ResetPassword)
+	}
+}
+
+func (h *passwordResetHandler) ForgotPassword(c *gin.Context) {
+	var forgotPasswordRequest struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	if err := c.ShouldBind(&forgotPasswordRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&forgotPasswordRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.passwordResetUseCase.ForgotPassword(c.Request.Context(), forgotPasswordRequest.Email, c.ClientIP()); err != nil {
+		var throttledErr *password_reset_usecase.ThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the email is registered, a password reset link has been sent"})
+}
+
+func (h *passwordResetHandler) ResetPassword(c *gin.Context) {
+	var resetPasswordRequest struct {
+		Token              string `json:"token" validate:"required"`
+		NewPassword        string `json:"new_password" validate:"required,min=8"`
+		ConfirmNewPassword string `json:"confirm_new_password" validate:"required,eqfield=NewPassword"`
+	}
+
+	if err := c.ShouldBind(&resetPasswordRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&resetPasswordRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.passwordResetUseCase.ResetPassword(c.Request.Context(), resetPasswordRequest.Token, resetPasswordRequest.NewPassword); err != nil {
+		if errors.Is(err, password_reset_usecase.ErrInvalidResetToken) || errors.Is(err, password_reset_usecase.ErrPasswordTooShort) {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
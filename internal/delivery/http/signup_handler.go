@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/signup_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type ISignupHandler interface {
+	RegisterRoutes(r *gin.Engine)
+	Signup(c *gin.Context)
+	Invite(c *gin.Context)
+}
+
+type signupHandler struct {
+	signupUseCase           signup_usecase.ISignupUseCase
+	jwtMiddleware           middlewares.IJWTMiddleware
+	authorizationMiddleware middlewares.IAuthorizationMiddleware
+	validator               *validator.Validate
+}
+
+func NewSignupHandler(signupUseCase signup_usecase.ISignupUseCase, middleware middlewares.IJWTMiddleware, authorizationMiddleware middlewares.IAuthorizationMiddleware) ISignupHandler {
+	return &signupHandler{
+		signupUseCase:           signupUseCase,
+		jwtMiddleware:           middleware,
+		authorizationMiddleware: authorizationMiddleware,
+		validator:               validator.New(),
+	}
+}
+
+func (h *signupHandler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/api/v1/auth/signup", h.Signup)
+	r.POST("/api/v1/admin/invitations",
+		h.jwtMiddleware.Middleware(),
+		h.authorizationMiddleware.RequirePermission(domain.PermissionInvitationsCreate),
+		h.Invite,
+	)
+}
+
+func (h *signupHandler) Signup(c *gin.Context) {
+	var signupRequest struct {
+		FirstName      string `json:"first_name" validate:"required,max=155"`
+		LastName       string `json:"last_name" validate:"required,max=155"`
+		Email          string `json:"email" validate:"required,email,max=155"`
+		Password       string `json:"password" validate:"required,min=8"`
+		InvitationCode string `json:"invitation_code"`
+	}
+
+	if err := c.ShouldBind(&signupRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&signupRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	user := domain.User{
+		FirstName: signupRequest.FirstName,
+		LastName:  signupRequest.LastName,
+		Email:     signupRequest.Email,
+		Password:  signupRequest.Password,
+	}
+
+	if err := h.signupUseCase.Signup(c.Request.Context(), &user, signupRequest.InvitationCode, c.ClientIP()); err != nil {
+		var throttledErr *signup_usecase.ThrottledError
+		switch {
+		case errors.As(err, &throttledErr):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, helpers.ErrorResponse(c, err.Error()))
+		case errors.Is(err, signup_usecase.ErrSignupDisabled), errors.Is(err, signup_usecase.ErrEmailDomainNotAllowed), errors.Is(err, signup_usecase.ErrInvalidInvitation):
+			c.JSON(http.StatusForbidden, helpers.ErrorResponse(c, err.Error()))
+		case errors.Is(err, user_usecase.ErrEmailAlreadyRegistered):
+			c.JSON(http.StatusConflict, helpers.ErrorResponse(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "User created successfully", "user": user})
+}
+
+func (h *signupHandler) Invite(c *gin.Context) {
+	userID, err := helpers.GetUserIDInContextRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Unauthorized"))
+		return
+	}
+
+	var inviteRequest struct {
+		Email string `json:"email" validate:"required,email,max=155"`
+	}
+
+	if err := c.ShouldBind(&inviteRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&inviteRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	invitation, code, err := h.signupUseCase.Invite(c.Request.Context(), inviteRequest.Email, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Invitation sent", "invitation": invitation, "invitation_code": code})
+}
@@ -20,49 +20,61 @@ type IUserHandler interface {
 	GetUser(c *gin.Context)
 	UpdateUser(c *gin.Context)
 	DeleteUser(c *gin.Context)
-	ResetPassword(c *gin.Context)
+	GetUserRoles(c *gin.Context)
+	AssignRoles(c *gin.Context)
 }
 
 type userHandler struct {
-	userUseCase   user_usecase.IUserUseCase
-	jwtMiddleware middlewares.IJWTMiddleware
-	validator     *validator.Validate
+	userUseCase             user_usecase.IUserUseCase
+	jwtMiddleware           middlewares.IJWTMiddleware
+	authorizationMiddleware middlewares.IAuthorizationMiddleware
+	validator               *validator.Validate
 }
 
-func NewUserHandler(us user_usecase.IUserUseCase, middleware middlewares.IJWTMiddleware) IUserHandler {
+func NewUserHandler(us user_usecase.IUserUseCase, middleware middlewares.IJWTMiddleware, authorizationMiddleware middlewares.IAuthorizationMiddleware) IUserHandler {
 	return &userHandler{
-		userUseCase:   us,
-		jwtMiddleware: middleware,
-		validator:     validator.New(),
+		userUseCase:             us,
+		jwtMiddleware:           middleware,
+		authorizationMiddleware: authorizationMiddleware,
+		validator:               validator.New(),
 	}
 }
 
 func (uh *userHandler) RegisterRoutes(r *gin.Engine) {
+	can := uh.authorizationMiddleware.RequirePermission
+	canOrSelf := uh.authorizationMiddleware.RequirePermissionOrSelf
+
 	userGroup := r.Group("/api/v1/users", uh.jwtMiddleware.Middleware())
 	{
-		userGroup.POST("/", uh.Register)
-		userGroup.GET("/", uh.GetAllUsers)
-		userGroup.GET("/:id", uh.GetUser)
-		userGroup.PUT("/:id", uh.UpdateUser)
-		userGroup.DELETE("/:id", uh.DeleteUser)
-		userGroup.POST("/reset_password", uh.ResetPassword)
+		userGroup.POST("/", can(domain.PermissionUsersCreate), uh.Register)
+		userGroup.GET("/", can(domain.PermissionUsersRead), uh.GetAllUsers)
+		userGroup.GET("/:id", canOrSelf(domain.PermissionUsersRead, "id"), uh.GetUser)
+		userGroup.PUT("/:id", canOrSelf(domain.PermissionUsersUpdate, "id"), uh.UpdateUser)
+		userGroup.DELETE("/:id", canOrSelf(domain.PermissionUsersDelete, "id"), uh.DeleteUser)
+		userGroup.GET("/:id/roles", canOrSelf(domain.PermissionUsersRead, "id"), uh.GetUserRoles)
+		userGroup.PUT("/:id/roles", can(domain.PermissionRolesAssign), uh.AssignRoles)
 	}
 }
 
 func (uh *userHandler) Register(c *gin.Context) {
 	var user domain.User
 	if err := c.ShouldBind(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
 	if err := uh.validator.Struct(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	if err := uh.userUseCase.Register(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := uh.userUseCase.Register(c.Request.Context(), &user); err != nil {
+		if errors.Is(err, user_usecase.ErrEmailAlreadyRegistered) {
+			c.JSON(http.StatusConflict, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
@@ -74,18 +86,18 @@ func (uh *userHandler) GetAllUsers(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.Query("limit"))
 
 	if offset < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "offset cannot be negative"})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, "offset cannot be negative"))
 		return
 	}
 
 	if limit <= 0 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "limit cannot be negative, zero or greater than 100"})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, "limit cannot be negative, zero or greater than 100"))
 		return
 	}
 
-	users, total, err := uh.userUseCase.GetAllUsers(offset, limit)
+	users, total, err := uh.userUseCase.GetAllUsers(c.Request.Context(), offset, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
@@ -107,18 +119,18 @@ func (uh *userHandler) GetAllUsers(c *gin.Context) {
 func (uh *userHandler) GetUser(c *gin.Context) {
 	id := c.Param("id")
 	if err := helpers.IsValidUUIDv4(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	user, err := uh.userUseCase.GetUser(id)
+	user, err := uh.userUseCase.GetUser(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+			c.JSON(http.StatusNotFound, helpers.ErrorResponse(c, "User not found"))
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
@@ -128,24 +140,33 @@ func (uh *userHandler) GetUser(c *gin.Context) {
 func (uh *userHandler) UpdateUser(c *gin.Context) {
 	userID := c.Param("id")
 	if err := helpers.IsValidUUIDv4(userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
 	var user domain.User
 	if err := c.ShouldBind(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
 	if err := uh.validator.Struct(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
 	user.ID = userID
-	if err := uh.userUseCase.UpdateUser(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := uh.userUseCase.UpdateUser(c.Request.Context(), &user); err != nil {
+		if errors.Is(err, user_usecase.ErrEmailAlreadyRegistered) {
+			c.JSON(http.StatusConflict, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+		if errors.Is(err, user_usecase.ErrEmailDomainNotAllowed) {
+			c.JSON(http.StatusForbidden, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
@@ -155,50 +176,73 @@ func (uh *userHandler) UpdateUser(c *gin.Context) {
 func (uh *userHandler) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
 	if err := helpers.IsValidUUIDv4(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	if err := uh.userUseCase.DeleteUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := uh.userUseCase.DeleteUser(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Delete user"})
 }
 
-func (uh *userHandler) ResetPassword(c *gin.Context) {
-	userID, err := helpers.GetUserIDInContextRequest(c)
+func (uh *userHandler) GetUserRoles(c *gin.Context) {
+	id := c.Param("id")
+	if err := helpers.IsValidUUIDv4(id); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+		return
+	}
+
+	roles, err := uh.userUseCase.GetUserRoles(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, helpers.ErrorResponse(c, "User not found"))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	if err := helpers.IsValidUUIDv4(userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+func (uh *userHandler) AssignRoles(c *gin.Context) {
+	id := c.Param("id")
+	if err := helpers.IsValidUUIDv4(id); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	var resetPasswordRequest struct {
-		OldPassword        string `json:"old_password" validate:"required"`
-		NewPassword        string `json:"new_password" validate:"required"`
-		ConfirmNewPassword string `json:"confirm_new_password" validate:"required"`
+	var assignRolesRequest struct {
+		Roles []string `json:"roles" validate:"required,dive,required"`
 	}
 
-	if err := c.ShouldBind(&resetPasswordRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBind(&assignRolesRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	if err := uh.validator.Struct(&resetPasswordRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := uh.validator.Struct(&assignRolesRequest); err != nil {
+		c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	if err := uh.userUseCase.ResetPassword(userID, resetPasswordRequest.OldPassword, resetPasswordRequest.NewPassword); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := uh.userUseCase.AssignRoles(c.Request.Context(), id, assignRolesRequest.Roles); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, helpers.ErrorResponse(c, "User not found"))
+			return
+		}
+		if errors.Is(err, user_usecase.ErrRoleNotFound) {
+			c.JSON(http.StatusBadRequest, helpers.ErrorResponse(c, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, helpers.ErrorResponse(c, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+	c.JSON(http.StatusOK, gin.H{"message": "Roles assigned successfully", "roles": assignRolesRequest.Roles})
 }
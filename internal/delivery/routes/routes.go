@@ -2,20 +2,35 @@ package routes
 
 import (
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/delivery/http"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/middlewares"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/auth_usecase"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
-	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(r *gin.Engine) {
-	jwtMiddleware := middlewares.NewJWTMiddleware()
-
-	authHandler := http.NewAuthHandler(auth_usecase.NewAuthUseCase(mysql.NewUserRepository(), jwt_usecase.NewJWTUseCase()))
-	authHandler.RegisterRoutes(r)
+// Handlers are the HTTP handlers served by the router, built by the
+// composition root in internal/app.
+type Handlers struct {
+	Health            http.IHealthHandler
+	Metrics           http.IMetricsHandler
+	Auth              http.IAuthHandler
+	MFA               http.IMFAHandler
+	User              http.IUserHandler
+	Me                http.IMeHandler
+	Signup            http.ISignupHandler
+	PasswordReset     http.IPasswordResetHandler
+	EmailVerification http.IEmailVerificationHandler
+	Lockout           http.ILockoutHandler
+	JWKS              http.IJWKSHandler
+}
 
-	userHandler := http.NewUserHandler(user_usecase.NewUserUseCase(mysql.NewUserRepository()), jwtMiddleware)
-	userHandler.RegisterRoutes(r)
+func RegisterRoutes(r *gin.Engine, handlers Handlers) {
+	handlers.Health.RegisterRoutes(r)
+	handlers.Metrics.RegisterRoutes(r)
+	handlers.Auth.RegisterRoutes(r)
+	handlers.MFA.RegisterRoutes(r)
+	handlers.User.RegisterRoutes(r)
+	handlers.Me.RegisterRoutes(r)
+	handlers.Signup.RegisterRoutes(r)
+	handlers.PasswordReset.RegisterRoutes(r)
+	handlers.EmailVerification.RegisterRoutes(r)
+	handlers.Lockout.RegisterRoutes(r)
+	handlers.JWKS.RegisterRoutes(r)
 }
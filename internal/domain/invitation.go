@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Invitation lets one person sign up with the invited address while signup is
+// invite-only.
+type Invitation struct {
+	ID        string     `gorm:"type:char(36);primary_key;not null;unique" json:"id"`
+	Email     string     `gorm:"type:varchar(155);not null;index" json:"email"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	InvitedBy string     `gorm:"type:char(36);not null" json:"invited_by"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp" json:"created_at"`
+}
+
+func (Invitation) TableName() string {
+	return "invitations"
+}
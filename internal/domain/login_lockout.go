@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	LockoutScopeUser = "user"
+	LockoutScopeIP   = "ip"
+)
+
+// LoginLockout counts consecutive failed logins for one user or client IP and
+// records how long further attempts are refused.
+type LoginLockout struct {
+	Scope          string     `gorm:"type:varchar(10);primary_key;not null" json:"scope"`
+	Subject        string     `gorm:"type:varchar(64);primary_key;not null" json:"subject"`
+	FailedAttempts int        `gorm:"not null;default:0" json:"failed_attempts"`
+	Lockouts       int        `gorm:"not null;default:0" json:"lockouts"`
+	LockedUntil    *time.Time `gorm:"type:timestamp;null" json:"locked_until"`
+	LastFailureAt  time.Time  `gorm:"type:timestamp" json:"last_failure_at"`
+	UpdatedAt      time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+func (LoginLockout) TableName() string {
+	return "login_lockouts"
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+type PasswordResetToken struct {
+	ID        string     `gorm:"type:char(36);primary_key;not null;unique" json:"id"`
+	UserID    string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp" json:"created_at"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
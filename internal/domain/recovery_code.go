@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// RecoveryCode is a single-use fallback for a lost TOTP authenticator.
+type RecoveryCode struct {
+	ID        string     `gorm:"type:char(36);primary_key;not null;unique" json:"id"`
+	UserID    string     `gorm:"type:char(36);not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:char(64);not null" json:"-"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp" json:"created_at"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
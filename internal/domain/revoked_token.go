@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// RevokedToken blacklists a single access token by its jti claim until it expires.
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;type:char(36);primary_key;not null;unique" json:"jti"`
+	UserID    string    `gorm:"type:char(36);not null;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"type:timestamp;not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// UserTokenRevocation invalidates every access token issued to a user before
+// RevokedAt, a whole second since iat has no finer precision.
+type UserTokenRevocation struct {
+	UserID    string    `gorm:"type:char(36);primary_key;not null;unique" json:"user_id"`
+	RevokedAt time.Time `gorm:"type:timestamp;not null" json:"revoked_at"`
+	ExpiresAt time.Time `gorm:"type:timestamp;not null;index" json:"expires_at"`
+}
+
+func (UserTokenRevocation) TableName() string {
+	return "user_token_revocations"
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+const (
+	PermissionUsersCreate       = "users:create"
+	PermissionUsersRead         = "users:read"
+	PermissionUsersUpdate       = "users:update"
+	PermissionUsersDelete       = "users:delete"
+	PermissionRolesAssign       = "roles:assign"
+	PermissionLockoutsManage    = "lockouts:manage"
+	PermissionInvitationsCreate = "invitations:create"
+)
+
+// DefaultRolePermissions lists the built-in roles and what they grant. Users
+// can always read, update and delete their own account without a permission.
+var DefaultRolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermissionUsersCreate,
+		PermissionUsersRead,
+		PermissionUsersUpdate,
+		PermissionUsersDelete,
+		PermissionRolesAssign,
+		PermissionLockoutsManage,
+		PermissionInvitationsCreate,
+	},
+	RoleUser: {},
+}
+
+type Permission struct {
+	ID        string    `gorm:"type:char(36);primary_key;not null;unique" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+type Role struct {
+	ID          string       `gorm:"type:char(36);primary_key;not null;unique" json:"id"`
+	Name        string       `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"type:timestamp" json:"updated_at"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+// UserRole is the join table granting a role to a user.
+type UserRole struct {
+	UserID    string    `gorm:"type:char(36);primary_key;not null" json:"user_id"`
+	RoleID    string    `gorm:"type:char(36);primary_key;not null;index" json:"role_id"`
+	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
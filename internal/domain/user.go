@@ -15,6 +15,12 @@ type User struct {
 	Active    bool      `gorm:"not null;default:true" json:"active"`
 	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamp" json:"updated_at"`
+
+	EmailVerifiedAt *time.Time `gorm:"type:timestamp;null" json:"-"`
+
+	TOTPSecret       string `gorm:"column:totp_secret;type:varchar(64)" json:"-"`
+	TOTPEnabled      bool   `gorm:"column:totp_enabled;not null;default:false" json:"-"`
+	TOTPLastUsedStep int64  `gorm:"column:totp_last_used_step;not null;default:0" json:"-"`
 }
 
 func (User) TableName() string {
@@ -24,22 +30,26 @@ func (User) TableName() string {
 func (u User) MarshalJSON() ([]byte, error) {
 	type Alias User
 	return json.Marshal(&struct {
-		ID        string    `json:"id"`
-		FirstName string    `json:"first_name"`
-		LastName  string    `json:"last_name"`
-		FullName  string    `json:"full_name"`
-		Email     string    `json:"email"`
-		Active    bool      `json:"active"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+		ID              string     `json:"id"`
+		FirstName       string     `json:"first_name"`
+		LastName        string     `json:"last_name"`
+		FullName        string     `json:"full_name"`
+		Email           string     `json:"email"`
+		Active          bool       `json:"active"`
+		EmailVerifiedAt *time.Time `json:"email_verified_at"`
+		MFAEnabled      bool       `json:"mfa_enabled"`
+		CreatedAt       time.Time  `json:"created_at"`
+		UpdatedAt       time.Time  `json:"updated_at"`
 	}{
-		ID:        u.ID,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		FullName:  u.FullName,
-		Email:     u.Email,
-		Active:    u.Active,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:              u.ID,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		FullName:        u.FullName,
+		Email:           u.Email,
+		Active:          u.Active,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		MFAEnabled:      u.TOTPEnabled,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
 	})
 }
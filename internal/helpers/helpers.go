@@ -1,9 +1,14 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"time"
 )
 
 func IsValidUUIDv4(u string) error {
@@ -27,3 +32,52 @@ func GetUserIDInContextRequest(c *gin.Context) (string, error) {
 
 	return userID.(string), nil
 }
+
+// GetRolesInContextRequest returns the roles of the authenticated user, or
+// none when the request carries no access token.
+func GetRolesInContextRequest(c *gin.Context) []string {
+	return c.GetStringSlice("roles")
+}
+
+// GetRequestIDInContextRequest returns the ID correlating the request with
+// the server logs.
+func GetRequestIDInContextRequest(c *gin.Context) string {
+	return c.GetString("requestID")
+}
+
+// ErrorResponse is the JSON body of every failed request. The request ID
+// lets a client report the failure and operators find it in the logs.
+func ErrorResponse(c *gin.Context, message string) gin.H {
+	return gin.H{"error": message, "request_id": GetRequestIDInContextRequest(c)}
+}
+
+func GetTokenIDInContextRequest(c *gin.Context) (string, time.Time, error) {
+	tokenID, ok := c.Get("tokenID")
+	if !ok {
+		return "", time.Time{}, errors.New("unauthorized")
+	}
+
+	jti, ok := tokenID.(string)
+	if !ok {
+		return "", time.Time{}, errors.New("token has no jti claim")
+	}
+
+	expiresAt := c.GetTime("tokenExpiresAt")
+
+	return jti, expiresAt, nil
+}
+
+// GenerateRandomToken returns a URL-safe opaque token built from size random bytes.
+func GenerateRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded SHA-256 of an opaque token, suitable for storage.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/authorization_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// IAuthorizationMiddleware checks the roles the JWT middleware put in the
+// context, so its handlers must be registered after IJWTMiddleware's.
+type IAuthorizationMiddleware interface {
+	RequirePermission(permission string) gin.HandlerFunc
+	RequirePermissionOrSelf(permission, idParam string) gin.HandlerFunc
+}
+
+type authorizationMiddleware struct {
+	authorizationUseCase authorization_usecase.IAuthorizationUseCase
+}
+
+func NewAuthorizationMiddleware(authorizationUseCase authorization_usecase.IAuthorizationUseCase) IAuthorizationMiddleware {
+	return &authorizationMiddleware{authorizationUseCase: authorizationUseCase}
+}
+
+func (m *authorizationMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.authorize(c, permission)
+	}
+}
+
+// RequirePermissionOrSelf lets the request through without permission when
+// the idParam path parameter is the caller's own user ID.
+func (m *authorizationMiddleware) RequirePermissionOrSelf(permission, idParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := helpers.GetUserIDInContextRequest(c)
+		if err == nil && userID == c.Param(idParam) {
+			c.Next()
+			return
+		}
+
+		m.authorize(c, permission)
+	}
+}
+
+func (m *authorizationMiddleware) authorize(c *gin.Context, permission string) {
+	allowed, err := m.authorizationUseCase.HasPermission(c.Request.Context(), helpers.GetRolesInContextRequest(c), permission)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ErrorResponse(c, "Failed to check permissions"))
+		return
+	}
+
+	if !allowed {
+		c.AbortWithStatusJSON(http.StatusForbidden, helpers.ErrorResponse(c, "Missing permission "+permission))
+		return
+	}
+
+	c.Next()
+}
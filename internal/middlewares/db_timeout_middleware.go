@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IDBTimeoutMiddleware interface {
+	Middleware() gin.HandlerFunc
+}
+
+type dbTimeoutMiddleware struct {
+	timeout time.Duration
+}
+
+func NewDBTimeoutMiddleware(timeout time.Duration) IDBTimeoutMiddleware {
+	return &dbTimeoutMiddleware{timeout: timeout}
+}
+
+// Middleware puts a deadline on the request context. Every repository runs
+// its queries with that context, so a slow database cannot hold a request
+// past the timeout, and a client that disconnects cancels its queries.
+func (m *dbTimeoutMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), m.timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -1,9 +1,12 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"strings"
 )
@@ -16,33 +19,89 @@ type jwtMiddleware struct {
 	jwtUseCase jwt_usecase.IJWTUseCase
 }
 
-func NewJWTMiddleware() IJWTMiddleware {
-	return &jwtMiddleware{jwtUseCase: jwt_usecase.NewJWTUseCase()}
+func NewJWTMiddleware(jwtUseCase jwt_usecase.IJWTUseCase) IJWTMiddleware {
+	return &jwtMiddleware{jwtUseCase: jwtUseCase}
 }
 
 func (m *jwtMiddleware) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ErrorResponse(c, "Authorization header missing"))
 			return
 		}
 
-		tokenString := strings.TrimSpace(strings.Replace(authHeader, "Bearer", "", 1))
-		token, err := m.jwtUseCase.ValidateToken(tokenString)
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		scheme, tokenString, found := strings.Cut(strings.TrimSpace(authHeader), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(tokenString) == "" {
+			abortWithAuthenticateError(c, http.StatusBadRequest, "invalid_request", "Authorization header must use the Bearer scheme")
+			return
+		}
+
+		token, err := m.jwtUseCase.ValidateToken(c.Request.Context(), strings.TrimSpace(tokenString))
+		if err != nil {
+			description, known := tokenErrorDescriptions[unwrapTokenError(err)]
+			if !known {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ErrorResponse(c, "Failed to validate token"))
+				return
+			}
+			abortWithAuthenticateError(c, http.StatusUnauthorized, "invalid_token", description)
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			abortWithAuthenticateError(c, http.StatusUnauthorized, "invalid_token", "Invalid token claims")
 			return
 		}
 
 		c.Set("userID", claims["user_id"])
+		c.Set("tokenID", claims["jti"])
+		c.Set("roles", rolesFromClaims(claims))
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			c.Set("tokenExpiresAt", exp.Time)
+		}
 
 		c.Next()
 	}
 }
+
+var tokenErrorDescriptions = map[error]string{
+	jwt_usecase.ErrTokenMalformed:     "The access token is malformed",
+	jwt_usecase.ErrTokenBadSignature:  "The access token signature is invalid",
+	jwt_usecase.ErrTokenExpired:       "The access token expired",
+	jwt_usecase.ErrTokenNotYetValid:   "The access token is not valid yet",
+	jwt_usecase.ErrTokenWrongAudience: "The access token was issued for another audience",
+	jwt_usecase.ErrTokenWrongIssuer:   "The access token was issued by an untrusted issuer",
+	jwt_usecase.ErrTokenInvalidClaims: "The access token claims are invalid",
+	jwt_usecase.ErrTokenRevoked:       "The access token has been revoked",
+	jwt_usecase.ErrTokenWrongPurpose:  "The token is not an access token",
+}
+
+func rolesFromClaims(claims jwt.MapClaims) []string {
+	values, _ := claims["roles"].([]interface{})
+
+	roles := make([]string, 0, len(values))
+	for _, value := range values {
+		if role, ok := value.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// unwrapTokenError returns the jwt_usecase sentinel carried by err, if any.
+func unwrapTokenError(err error) error {
+	for sentinel := range tokenErrorDescriptions {
+		if errors.Is(err, sentinel) {
+			return sentinel
+		}
+	}
+	return err
+}
+
+// abortWithAuthenticateError answers with an RFC 6750 Bearer challenge.
+func abortWithAuthenticateError(c *gin.Context, status int, code, description string) {
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer realm="api", error=%q, error_description=%q`, code, description))
+	c.AbortWithStatusJSON(status, helpers.ErrorResponse(c, description))
+}
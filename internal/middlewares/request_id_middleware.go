@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+type IRequestIDMiddleware interface {
+	Middleware() gin.HandlerFunc
+}
+
+type requestIDMiddleware struct{}
+
+func NewRequestIDMiddleware() IRequestIDMiddleware {
+	return &requestIDMiddleware{}
+}
+
+// Middleware keeps the X-Request-ID sent by the client, or generates one when
+// it is missing or malformed, and makes it available to handlers, log lines
+// and database calls. The ID is echoed back in the response header.
+func (m *requestIDMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestid.Header)
+		if !requestid.Valid(requestID) {
+			requestID = requestid.New()
+		}
+
+		c.Set("requestID", requestID)
+		c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), requestID))
+		c.Header(requestid.Header, requestID)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"gorm.io/gorm"
+)
+
+type IInvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.Invitation, error)
+	MarkUsed(ctx context.Context, id string) (bool, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) IInvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	return conn(ctx, r.db).Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	if err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Invitation{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
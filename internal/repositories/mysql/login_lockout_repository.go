@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ILoginLockoutRepository interface {
+	Find(ctx context.Context, scope, subject string) (*domain.LoginLockout, error)
+	Modify(ctx context.Context, scope, subject string, fn func(lockout *domain.LoginLockout)) (*domain.LoginLockout, error)
+	FindActive(ctx context.Context, now time.Time, offset, limit int) (*[]domain.LoginLockout, int64, error)
+	Delete(ctx context.Context, scope, subject string) error
+}
+
+type loginLockoutRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginLockoutRepository(db *gorm.DB) ILoginLockoutRepository {
+	return &loginLockoutRepository{db: db}
+}
+
+// Find returns the counters for the subject, or nil when it never failed.
+func (r *loginLockoutRepository) Find(ctx context.Context, scope, subject string) (*domain.LoginLockout, error) {
+	var lockout domain.LoginLockout
+	err := conn(ctx, r.db).Where("scope = ? AND subject = ?", scope, subject).First(&lockout).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &lockout, nil
+}
+
+// Modify applies fn to the row under a row lock, creating it first if needed,
+// so concurrent failed logins can't lose each other's increments.
+func (r *loginLockoutRepository) Modify(ctx context.Context, scope, subject string, fn func(lockout *domain.LoginLockout)) (*domain.LoginLockout, error) {
+	var lockout domain.LoginLockout
+
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("scope = ? AND subject = ?", scope, subject).
+			First(&lockout).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			lockout = domain.LoginLockout{Scope: scope, Subject: subject}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lockout).Error; err != nil {
+				return err
+			}
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("scope = ? AND subject = ?", scope, subject).
+				First(&lockout).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		fn(&lockout)
+
+		return tx.Save(&lockout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lockout, nil
+}
+
+func (r *loginLockoutRepository) FindActive(ctx context.Context, now time.Time, offset, limit int) (*[]domain.LoginLockout, int64, error) {
+	var lockouts []domain.LoginLockout
+	var total int64
+
+	active := "locked_until > ? OR failed_attempts > 0"
+	if err := conn(ctx, r.db).Model(&domain.LoginLockout{}).Where(active, now).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := conn(ctx, r.db).
+		Where(active, now).
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&lockouts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &lockouts, total, nil
+}
+
+func (r *loginLockoutRepository) Delete(ctx context.Context, scope, subject string) error {
+	return conn(ctx, r.db).Where("scope = ? AND subject = ?", scope, subject).Delete(&domain.LoginLockout{}).Error
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"gorm.io/gorm"
+)
+
+type IPasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id string) (bool, error)
+	DeleteForUser(ctx context.Context, userID string) error
+}
+
+type passwordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) IPasswordResetTokenRepository {
+	return &passwordResetTokenRepository{db: db}
+}
+
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	return conn(ctx, r.db).Create(token).Error
+}
+
+func (r *passwordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	if err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *passwordResetTokenRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *passwordResetTokenRepository) DeleteForUser(ctx context.Context, userID string) error {
+	return conn(ctx, r.db).Where("user_id = ?", userID).Delete(&domain.PasswordResetToken{}).Error
+}
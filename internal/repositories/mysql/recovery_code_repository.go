@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"gorm.io/gorm"
+)
+
+type IRecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID string, codes []domain.RecoveryCode) error
+	FindUnusedByHash(ctx context.Context, userID, codeHash string) (*domain.RecoveryCode, error)
+	MarkUsed(ctx context.Context, id string) (bool, error)
+	DeleteForUser(ctx context.Context, userID string) error
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) IRecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// ReplaceForUser discards every previous code of the user, used or not.
+func (r *recoveryCodeRepository) ReplaceForUser(ctx context.Context, userID string, codes []domain.RecoveryCode) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *recoveryCodeRepository) FindUnusedByHash(ctx context.Context, userID, codeHash string) (*domain.RecoveryCode, error) {
+	var code domain.RecoveryCode
+	err := conn(ctx, r.db).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).First(&code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (r *recoveryCodeRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *recoveryCodeRepository) DeleteForUser(ctx context.Context, userID string) error {
+	return conn(ctx, r.db).Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
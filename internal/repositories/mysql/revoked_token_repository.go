@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRevokedTokenRepository interface {
+	Create(ctx context.Context, token *domain.RevokedToken) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	RevokeUser(ctx context.Context, revocation *domain.UserTokenRevocation) error
+	FindUserRevocation(ctx context.Context, userID string) (*domain.UserTokenRevocation, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type revokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) IRevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+func (r *revokedTokenRepository) Create(ctx context.Context, token *domain.RevokedToken) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *revokedTokenRepository) RevokeUser(ctx context.Context, revocation *domain.UserTokenRevocation) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at", "expires_at"}),
+	}).Create(revocation).Error
+}
+
+func (r *revokedTokenRepository) FindUserRevocation(ctx context.Context, userID string) (*domain.UserTokenRevocation, error) {
+	var revocation domain.UserTokenRevocation
+	err := conn(ctx, r.db).Where("user_id = ?", userID).First(&revocation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revocation, nil
+}
+
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	if err := conn(ctx, r.db).Where("expires_at < ?", now).Delete(&domain.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Where("expires_at < ?", now).Delete(&domain.UserTokenRevocation{}).Error
+}
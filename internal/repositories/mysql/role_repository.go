@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"context"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IRoleRepository interface {
+	Sync(ctx context.Context, name string, permissions []string) error
+	FindRoleNamesByUserID(ctx context.Context, userID string) ([]string, error)
+	FindPermissionNames(ctx context.Context, roleNames []string) ([]string, error)
+	SetUserRoles(ctx context.Context, userID string, roleNames []string) error
+	DeleteForUser(ctx context.Context, userID string) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) IRoleRepository {
+	return &roleRepository{db: db}
+}
+
+// Sync creates the role and any missing permission, then makes permissions
+// exactly the set the role grants.
+func (r *roleRepository) Sync(ctx context.Context, name string, permissions []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var role domain.Role
+		if err := tx.Where(domain.Role{Name: name}).Attrs(domain.Role{ID: uuid.NewString()}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+
+		granted := make([]domain.Permission, 0, len(permissions))
+		for _, permissionName := range permissions {
+			var permission domain.Permission
+			err := tx.Where(domain.Permission{Name: permissionName}).
+				Attrs(domain.Permission{ID: uuid.NewString()}).
+				FirstOrCreate(&permission).Error
+			if err != nil {
+				return err
+			}
+			granted = append(granted, permission)
+		}
+
+		return tx.Model(&role).Association("Permissions").Replace(granted)
+	})
+}
+
+func (r *roleRepository) FindRoleNamesByUserID(ctx context.Context, userID string) ([]string, error) {
+	var names []string
+	err := conn(ctx, r.db).Model(&domain.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Pluck("roles.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (r *roleRepository) FindPermissionNames(ctx context.Context, roleNames []string) ([]string, error) {
+	var names []string
+	if len(roleNames) == 0 {
+		return names, nil
+	}
+
+	err := conn(ctx, r.db).Model(&domain.Permission{}).
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name IN ?", roleNames).
+		Pluck("permissions.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// SetUserRoles replaces every role of the user. It returns
+// gorm.ErrRecordNotFound when one of roleNames does not exist.
+func (r *roleRepository) SetUserRoles(ctx context.Context, userID string, roleNames []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var roles []domain.Role
+		if len(roleNames) > 0 {
+			if err := tx.Where("name IN ?", roleNames).Find(&roles).Error; err != nil {
+				return err
+			}
+		}
+		if len(roles) != len(uniqueStrings(roleNames)) {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		if len(roles) == 0 {
+			return nil
+		}
+
+		userRoles := make([]domain.UserRole, 0, len(roles))
+		for _, role := range roles {
+			userRoles = append(userRoles, domain.UserRole{UserID: userID, RoleID: role.ID})
+		}
+		return tx.Create(&userRoles).Error
+	})
+}
+
+func (r *roleRepository) DeleteForUser(ctx context.Context, userID string) error {
+	return conn(ctx, r.db).Where("user_id = ?", userID).Delete(&domain.UserRole{}).Error
+}
+
+func uniqueStrings(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type (
+	txKey          struct{}
+	afterCommitKey struct{}
+)
+
+// ITransactor runs a unit of work in one database transaction. Repositories
+// called with the context handed to fn take part in it.
+type ITransactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) ITransactor {
+	return &transactor{db: db}
+}
+
+// InTx commits when fn returns nil and rolls back otherwise. Nested calls run
+// in a savepoint of the outer transaction. Functions passed to AfterCommit
+// run once the outermost transaction has committed.
+func (t *transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	parent, nested := ctx.Value(afterCommitKey{}).(*[]func())
+
+	var hooks []func()
+	ctx = context.WithValue(ctx, afterCommitKey{}, &hooks)
+
+	err := conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err != nil {
+		return err
+	}
+
+	if nested {
+		*parent = append(*parent, hooks...)
+		return nil
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction carried by ctx commits, and not at
+// all if it rolls back. Outside of a transaction fn runs right away.
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
+}
+
+// conn returns the transaction carried by ctx, or db outside of one.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,64 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAfterCommit(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transactor := NewTransactor(db)
+	errRollback := errors.New("rollback")
+
+	var ran []string
+	hook := func(name string) func() {
+		return func() { ran = append(ran, name) }
+	}
+
+	AfterCommit(context.Background(), hook("outside"))
+
+	err = transactor.InTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, hook("committed"))
+		if len(ran) != 1 {
+			t.Errorf("hook ran before commit: %v", ran)
+		}
+
+		// A rolled back savepoint drops its hooks, the outer ones survive
+		_ = transactor.InTx(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, hook("rolled back savepoint"))
+			return errRollback
+		})
+		return transactor.InTx(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, hook("committed savepoint"))
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = transactor.InTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, hook("rolled back"))
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("InTx() error = %v, want %v", err, errRollback)
+	}
+
+	want := []string{"outside", "committed", "committed savepoint"}
+	if len(ran) != len(want) {
+		t.Fatalf("hooks ran = %v, want %v", ran, want)
+	}
+	for i := range want {
+		if ran[i] != want[i] {
+			t.Fatalf("hooks ran = %v, want %v", ran, want)
+		}
+	}
+}
@@ -1,57 +1,58 @@
 package mysql
 
 import (
-	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/database"
+	"context"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
 	"gorm.io/gorm"
 )
 
 type IUserRepository interface {
-	Create(user *domain.User) error
-	FindByID(id string) (*domain.User, error)
-	FindByEmail(email string) (*domain.User, error)
-	FindAll(offset, limit int) (*[]domain.User, int64, error)
-	Update(user *domain.User) error
-	Delete(id string) error
+	Create(ctx context.Context, user *domain.User) error
+	FindByID(ctx context.Context, id string) (*domain.User, error)
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindAll(ctx context.Context, offset, limit int) (*[]domain.User, int64, error)
+	Update(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id string) error
+	UpdateTOTPLastUsedStep(ctx context.Context, id string, step int64) (bool, error)
 }
 
 type userRepository struct {
 	db *gorm.DB
 }
 
-func NewUserRepository() IUserRepository {
-	return &userRepository{db: database.GetDBInstance()}
+func NewUserRepository(db *gorm.DB) IUserRepository {
+	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	return conn(ctx, r.db).Create(user).Error
 }
 
-func (r *userRepository) FindByID(id string) (*domain.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("id = ? AND active = true", id).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("id = ? AND active = true", id).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("email = ? AND active = true", email).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("email = ? AND active = true", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindAll(offset, limit int) (*[]domain.User, int64, error) {
+func (r *userRepository) FindAll(ctx context.Context, offset, limit int) (*[]domain.User, int64, error) {
 	var users []domain.User
 	var total int64
 
-	if err := r.db.Model(&domain.User{}).Count(&total).Error; err != nil {
+	if err := conn(ctx, r.db).Model(&domain.User{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.
+	err := conn(ctx, r.db).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -63,10 +64,22 @@ func (r *userRepository) FindAll(offset, limit int) (*[]domain.User, int64, erro
 	return &users, total, nil
 }
 
-func (r *userRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	return conn(ctx, r.db).Save(user).Error
 }
 
-func (r *userRepository) Delete(id string) error {
-	return r.db.Delete(&domain.User{}, "id = ?", id).Error
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Delete(&domain.User{}, "id = ?", id).Error
+}
+
+// UpdateTOTPLastUsedStep records step as the latest accepted TOTP step. It
+// reports false when an equal or later step was already used.
+func (r *userRepository) UpdateTOTPLastUsedStep(ctx context.Context, id string, step int64) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.User{}).
+		Where("id = ? AND totp_last_used_step < ?", id, step).
+		Update("totp_last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
 }
@@ -0,0 +1,11 @@
+package types
+
+import "github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+
+type LockoutPaginationResponse struct {
+	Data        *[]domain.LoginLockout `json:"data"`
+	Total       int64                  `json:"total"`
+	PageSize    int                    `json:"page_size"`
+	CurrentPage int                    `json:"current_page"`
+	TotalPages  int                    `json:"total_pages"`
+}
@@ -1,38 +1,309 @@
 package auth_usecase
 
 import (
+	"context"
 	"errors"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/lockout_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/mfa_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/metrics"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/tracing"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	refreshTokenSize = 32
+	mfaChallengeTTL  = 5 * time.Minute
+
+	// dummyPasswordHash is compared against when no account matches the
+	// email, so unknown addresses take as long to reject as wrong passwords.
+	// Its cost must stay in line with the one used to hash real passwords.
+	dummyPasswordHash = "$2a$10$/biDFcZ3rjqhyxWEZHM33OqK2/3JidHosU2iloxo/nHA40kpjLuqK"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used, session revoked")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge, please log in again")
+	ErrEmailNotVerified    = errors.New("email address has not been verified")
+	ErrInvalidCredentials  = errors.New("email or password is incorrect")
 )
 
 type IAuthUseCase interface {
-	Login(email, password string) (string, error)
+	Login(ctx context.Context, email, password, clientIP string) (*types.AuthTokensResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*types.AuthTokensResponse, error)
+	Logout(ctx context.Context, userID, tokenID string, tokenExpiresAt time.Time, refreshToken string) error
+	VerifyMFA(ctx context.Context, mfaToken, code string) (*types.AuthTokensResponse, error)
 }
 
 type authUseCase struct {
-	userRepo   mysql.IUserRepository
-	jwtUseCase jwt_usecase.IJWTUseCase
+	userRepo         mysql.IUserRepository
+	refreshTokenRepo mysql.IRefreshTokenRepository
+	roleRepo         mysql.IRoleRepository
+	jwtUseCase       jwt_usecase.IJWTUseCase
+	mfaUseCase       mfa_usecase.IMFAUseCase
+	lockoutUseCase   lockout_usecase.ILockoutUseCase
+	metrics          *metrics.Metrics
+	refreshTokenTTL  time.Duration
+	requireVerified  bool
 }
 
-func NewAuthUseCase(userRepo mysql.IUserRepository, jwtUseCase jwt_usecase.IJWTUseCase) IAuthUseCase {
+func NewAuthUseCase(cfg config.AuthConfig, userRepo mysql.IUserRepository, refreshTokenRepo mysql.IRefreshTokenRepository, roleRepo mysql.IRoleRepository, jwtUseCase jwt_usecase.IJWTUseCase, mfaUseCase mfa_usecase.IMFAUseCase, lockoutUseCase lockout_usecase.ILockoutUseCase, appMetrics *metrics.Metrics) IAuthUseCase {
 	return &authUseCase{
-		userRepo:   userRepo,
-		jwtUseCase: jwtUseCase,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		roleRepo:         roleRepo,
+		jwtUseCase:       jwtUseCase,
+		mfaUseCase:       mfaUseCase,
+		lockoutUseCase:   lockoutUseCase,
+		metrics:          appMetrics,
+		refreshTokenTTL:  cfg.RefreshTokenTTL,
+		requireVerified:  cfg.RequireEmailVerification,
+	}
+}
+
+func (a *authUseCase) Login(ctx context.Context, email, password, clientIP string) (*types.AuthTokensResponse, error) {
+	ctx, span := tracing.Start(ctx, "authUseCase.Login")
+	tokens, err := a.login(ctx, email, password, clientIP)
+	tracing.End(span, err)
+
+	a.observeLogin(tokens, err)
+	return tokens, err
+}
+
+// login checks the client IP and the account against the failed-login
+// lockouts before comparing the password, so a locked account can't be probed.
+func (a *authUseCase) login(ctx context.Context, email, password, clientIP string) (*types.AuthTokensResponse, error) {
+	if err := a.lockoutUseCase.CheckIP(ctx, clientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := a.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = comparePassword(ctx, dummyPasswordHash, password)
+			return nil, a.registerFailure(ctx, "", clientIP)
+		}
+		return nil, err
+	}
+
+	if err := a.lockoutUseCase.CheckUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := comparePassword(ctx, user.Password, password); err != nil {
+		return nil, a.registerFailure(ctx, user.ID, clientIP)
+	}
+
+	if err := a.lockoutUseCase.RegisterSuccess(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if a.requireVerified && user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+
+	if user.TOTPEnabled {
+		mfaToken, err := a.jwtUseCase.GenerateScopedToken(user.ID, jwt_usecase.PurposeMFAPending, mfaChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AuthTokensResponse{MFARequired: true, MFAToken: mfaToken}, nil
 	}
+
+	// Every login starts a new token family; rotations inherit it.
+	return a.issueTokens(ctx, user.ID, uuid.NewString())
+}
+
+func comparePassword(ctx context.Context, hash, password string) error {
+	_, span := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
+	defer span.End()
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-func (a *authUseCase) Login(email, password string) (string, error) {
-	user, err := a.userRepo.FindByEmail(email)
+func (a *authUseCase) Refresh(ctx context.Context, refreshToken string) (*types.AuthTokensResponse, error) {
+	stored, err := a.refreshTokenRepo.FindByHash(ctx, helpers.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return nil, a.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	marked, err := a.refreshTokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Lost the race against another refresh of the same token.
+		return nil, a.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+
+	if _, err := a.userRepo.FindByID(ctx, stored.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return a.issueTokens(ctx, stored.UserID, stored.FamilyID)
+}
+
+// VerifyMFA exchanges the challenge token from Login plus a TOTP or recovery
+// code for a real token pair. A challenge survives a single attempt only, so
+// guessing codes requires a password login per guess.
+func (a *authUseCase) VerifyMFA(ctx context.Context, mfaToken, code string) (*types.AuthTokensResponse, error) {
+	tokens, err := a.verifyMFA(ctx, mfaToken, code)
+	a.observeLogin(tokens, err)
+	return tokens, err
+}
+
+func (a *authUseCase) verifyMFA(ctx context.Context, mfaToken, code string) (*types.AuthTokensResponse, error) {
+	challenge, err := a.jwtUseCase.ValidateScopedToken(ctx, mfaToken, jwt_usecase.PurposeMFAPending)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	claims, _ := challenge.Claims.(jwt.MapClaims)
+	userID, _ := claims["user_id"].(string)
+	jti, _ := claims["jti"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := a.jwtUseCase.RevokeToken(ctx, jti, userID, expiresAt.Time); err != nil {
+		return nil, err
+	}
+
+	user, err := a.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	if err := a.mfaUseCase.VerifyCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	return a.issueTokens(ctx, user.ID, uuid.NewString())
+}
+
+func (a *authUseCase) Logout(ctx context.Context, userID, tokenID string, tokenExpiresAt time.Time, refreshToken string) error {
+	if err := a.jwtUseCase.RevokeToken(ctx, tokenID, userID, tokenExpiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := a.refreshTokenRepo.FindByHash(ctx, helpers.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Never let one user end another user's session.
+	if stored.UserID != userID {
+		return nil
+	}
+
+	return a.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// observeLogin counts the outcome of a login step. A password accepted
+// pending MFA is counted once the second factor is checked, and internal
+// errors are no login outcome at all.
+func (a *authUseCase) observeLogin(tokens *types.AuthTokensResponse, err error) {
+	var lockedErr *lockout_usecase.LockedError
+	switch {
+	case err == nil && tokens.MFARequired:
+	case err == nil:
+		a.metrics.ObserveLogin(metrics.LoginSucceeded)
+	case errors.As(err, &lockedErr):
+		a.metrics.ObserveLogin(metrics.LoginLocked)
+	case errors.Is(err, ErrInvalidCredentials),
+		errors.Is(err, ErrEmailNotVerified),
+		errors.Is(err, ErrInvalidMFAChallenge),
+		errors.Is(err, mfa_usecase.ErrInvalidMFACode):
+		a.metrics.ObserveLogin(metrics.LoginFailed)
+	}
+}
+
+func (a *authUseCase) registerFailure(ctx context.Context, userID, clientIP string) error {
+	if err := a.lockoutUseCase.RegisterFailure(ctx, userID, clientIP); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+func (a *authUseCase) revokeReusedFamily(ctx context.Context, familyID string) error {
+	if err := a.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens reads the roles afresh, so a refresh picks up role changes.
+func (a *authUseCase) issueTokens(ctx context.Context, userID, familyID string) (*types.AuthTokensResponse, error) {
+	roles, err := a.roleRepo.FindRoleNamesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := a.jwtUseCase.GenerateToken(userID, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := helpers.GenerateRandomToken(refreshTokenSize)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = a.refreshTokenRepo.Create(ctx, &domain.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: helpers.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(a.refreshTokenTTL),
+	})
 	if err != nil {
-		return "", errors.New("email or password is incorrect")
+		return nil, err
 	}
 
-	return a.jwtUseCase.GenerateToken(user.ID)
+	return &types.AuthTokensResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(a.jwtUseCase.AccessTokenTTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(a.refreshTokenTTL.Seconds()),
+	}, nil
 }
@@ -0,0 +1,18 @@
+package auth_usecase
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// A malformed or cheaper dummy hash would make unknown emails fail fast again.
+func TestDummyPasswordHashMatchesPasswordCost(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("dummy hash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+}
@@ -0,0 +1,36 @@
+package authorization_usecase
+
+import (
+	"context"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+)
+
+type IAuthorizationUseCase interface {
+	HasPermission(ctx context.Context, roles []string, permission string) (bool, error)
+}
+
+type authorizationUseCase struct {
+	roleRepo mysql.IRoleRepository
+}
+
+func NewAuthorizationUseCase(roleRepo mysql.IRoleRepository) IAuthorizationUseCase {
+	return &authorizationUseCase{roleRepo: roleRepo}
+}
+
+// HasPermission reports whether any of roles grants permission. Roles come
+// from the access token, permissions from the database, so changing what a
+// role grants takes effect immediately.
+func (a *authorizationUseCase) HasPermission(ctx context.Context, roles []string, permission string) (bool, error) {
+	permissions, err := a.roleRepo.FindPermissionNames(ctx, roles)
+	if err != nil {
+		return false, err
+	}
+
+	for _, granted := range permissions {
+		if granted == permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,155 @@
+package email_verification_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/mailer"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/throttle"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidVerificationToken = errors.New("invalid or expired email verification link")
+
+// ThrottledError reports a resend refused because the client IP asked too
+// often, together with how long it has to wait.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many verification emails requested from this address, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+type IEmailVerificationUseCase interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+	Resend(ctx context.Context, email, clientIP string) error
+	Verify(ctx context.Context, token string) (*domain.User, error)
+}
+
+type emailVerificationUseCase struct {
+	userRepo        mysql.IUserRepository
+	jwtUseCase      jwt_usecase.IJWTUseCase
+	mailer          *mailer.Dispatcher
+	tokenTTL        time.Duration
+	verificationURL string
+	resendsPerIP    *throttle.Window
+	resendsPerEmail *throttle.Window
+}
+
+func NewEmailVerificationUseCase(cfg config.EmailVerificationConfig, userRepo mysql.IUserRepository, jwtUseCase jwt_usecase.IJWTUseCase, mail *mailer.Dispatcher) IEmailVerificationUseCase {
+	return &emailVerificationUseCase{
+		userRepo:        userRepo,
+		jwtUseCase:      jwtUseCase,
+		mailer:          mail,
+		tokenTTL:        cfg.TokenTTL,
+		verificationURL: cfg.URL,
+		resendsPerIP:    throttle.NewWindow(cfg.MaxPerIP, cfg.ThrottleWindow),
+		resendsPerEmail: throttle.NewWindow(1, cfg.Cooldown),
+	}
+}
+
+// SendVerification emails a signed verification link to the user. Accounts
+// that are already verified are skipped.
+func (e *emailVerificationUseCase) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, err := e.jwtUseCase.GenerateEmailVerificationToken(user.ID, user.Email, e.tokenTTL)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n",
+			user.FirstName, e.tokenTTL, e.verificationLink(token)),
+	}
+
+	e.mailer.Dispatch(msg)
+
+	return nil
+}
+
+// Resend sends a new link to an unverified account. Like the forgot-password
+// flow it never reports whether the address exists, and it sends at most one
+// email per address per cooldown. Only clients going over maxPerIP requests
+// per throttleWindow get a ThrottledError.
+func (e *emailVerificationUseCase) Resend(ctx context.Context, email, clientIP string) error {
+	if ok, retryAfter := e.resendsPerIP.Allow(clientIP); !ok {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	if ok, _ := e.resendsPerEmail.Allow(strings.ToLower(email)); !ok {
+		return nil
+	}
+
+	user, err := e.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return e.SendVerification(ctx, user)
+}
+
+func (e *emailVerificationUseCase) Verify(ctx context.Context, token string) (*domain.User, error) {
+	parsed, err := e.jwtUseCase.ValidateScopedToken(ctx, token, jwt_usecase.PurposeEmailVerification)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	claims, _ := parsed.Claims.(jwt.MapClaims)
+	userID, _ := claims["user_id"].(string)
+	email, _ := claims["email"].(string)
+
+	user, err := e.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	// The link only proves ownership of the address it was sent to.
+	if user.Email != email {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := e.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (e *emailVerificationUseCase) verificationLink(token string) string {
+	link, err := url.Parse(e.verificationURL)
+	if err != nil {
+		return e.verificationURL + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
@@ -0,0 +1,102 @@
+package email_verification_usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/mailer"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"gorm.io/gorm"
+)
+
+// userRepo finds one user by email case-insensitively, like MySQL does.
+type userRepo struct {
+	mysql.IUserRepository
+	user *domain.User
+}
+
+func (r *userRepo) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	if strings.EqualFold(email, r.user.Email) {
+		return r.user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type jwtUseCase struct {
+	jwt_usecase.IJWTUseCase
+}
+
+func (jwtUseCase) GenerateEmailVerificationToken(userID, email string, ttl time.Duration) (string, error) {
+	return "token", nil
+}
+
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent int
+}
+
+func (m *recordingMailer) Send(mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent++
+	return nil
+}
+
+func (m *recordingMailer) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sent
+}
+
+func newTestUseCase(maxPerIP int) (IEmailVerificationUseCase, *recordingMailer) {
+	mail := &recordingMailer{}
+	repo := &userRepo{user: &domain.User{ID: "user-id", Email: "user@example.com"}}
+	cfg := config.EmailVerificationConfig{
+		URL:            "http://localhost/verify",
+		TokenTTL:       time.Hour,
+		Cooldown:       time.Hour,
+		MaxPerIP:       maxPerIP,
+		ThrottleWindow: time.Hour,
+	}
+	return NewEmailVerificationUseCase(cfg, repo, jwtUseCase{}, mailer.NewDispatcher(mail, 4)), mail
+}
+
+func TestResendCooldownIgnoresCase(t *testing.T) {
+	uc, mail := newTestUseCase(10)
+
+	for _, email := range []string{"user@example.com", "User@Example.com", "USER@EXAMPLE.COM"} {
+		if err := uc.Resend(context.Background(), email, "192.0.2.1"); err != nil {
+			t.Fatalf("Resend(%s): %v", email, err)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if sent := mail.count(); sent != 1 {
+		t.Errorf("%d emails sent, want 1", sent)
+	}
+}
+
+func TestResendThrottlesClientIP(t *testing.T) {
+	uc, _ := newTestUseCase(2)
+
+	for i := 0; i < 2; i++ {
+		if err := uc.Resend(context.Background(), "unknown@example.com", "192.0.2.1"); err != nil {
+			t.Fatalf("resend %d: %v", i+1, err)
+		}
+	}
+
+	var throttledErr *ThrottledError
+	if err := uc.Resend(context.Background(), "unknown@example.com", "192.0.2.1"); !errors.As(err, &throttledErr) {
+		t.Fatalf("resend over the limit: error %v, want a ThrottledError", err)
+	}
+	if err := uc.Resend(context.Background(), "unknown@example.com", "192.0.2.2"); err != nil {
+		t.Errorf("another client IP was throttled: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package health_usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
+)
+
+// CheckFunc reports whether a dependency is usable. It must give up once ctx
+// is done.
+type CheckFunc func(ctx context.Context) error
+
+type IHealthChecker interface {
+	Register(name string, check CheckFunc)
+	Check(ctx context.Context) types.HealthReport
+}
+
+type healthChecker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// NewHealthChecker returns an empty registry whose checks each get at most
+// timeout to answer.
+func NewHealthChecker(timeout time.Duration) IHealthChecker {
+	return &healthChecker{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+// Register adds or replaces the check reported under name.
+func (h *healthChecker) Register(name string, check CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// Check runs every registered check concurrently. The report is only ok when
+// all components are.
+func (h *healthChecker) Check(ctx context.Context) types.HealthReport {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	h.mu.RUnlock()
+	sort.Strings(names)
+
+	results := make([]types.ComponentHealth, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		h.mu.RLock()
+		check := h.checks[name]
+		h.mu.RUnlock()
+
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = h.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := types.HealthReport{
+		Status:     types.HealthStatusOK,
+		Components: make(map[string]types.ComponentHealth, len(names)),
+	}
+	for i, name := range names {
+		report.Components[name] = results[i]
+		if results[i].Status != types.HealthStatusOK {
+			report.Status = types.HealthStatusUnavailable
+		}
+	}
+
+	return report
+}
+
+func (h *healthChecker) run(ctx context.Context, check CheckFunc) types.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// A check that ignores ctx must not hold up the whole report.
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := types.ComponentHealth{
+		Status:     types.HealthStatusOK,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = types.HealthStatusUnavailable
+		result.Error = err.Error()
+	}
+
+	return result
+}
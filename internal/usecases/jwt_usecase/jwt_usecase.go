@@ -1,51 +1,319 @@
 package jwt_usecase
 
 import (
-	"github.com/dgrijalva/jwt-go"
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"sync"
 	"time"
 )
 
+const revocationPurgeEvery = 10 * time.Minute
+
+var (
+	ErrTokenMalformed     = errors.New("token is malformed")
+	ErrTokenBadSignature  = errors.New("token signature is invalid")
+	ErrTokenExpired       = errors.New("token is expired")
+	ErrTokenNotYetValid   = errors.New("token is not valid yet")
+	ErrTokenWrongAudience = errors.New("token has invalid audience")
+	ErrTokenWrongIssuer   = errors.New("token has invalid issuer")
+	ErrTokenInvalidClaims = errors.New("token has invalid claims")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrTokenWrongPurpose  = errors.New("token was issued for another purpose")
+)
+
+const (
+	// PurposeMFAPending marks the challenge token handed out by a password
+	// login that still needs a second factor.
+	PurposeMFAPending = "mfa_pending"
+	// PurposeEmailVerification marks the token embedded in verification links.
+	PurposeEmailVerification = "email_verification"
+)
+
 type IJWTUseCase interface {
-	GenerateToken(userID string) (string, error)
-	ValidateToken(tokenString string) (*jwt.Token, error)
+	GenerateToken(userID string, roles []string) (string, error)
+	ValidateToken(ctx context.Context, tokenString string) (*jwt.Token, error)
+	GenerateScopedToken(userID, purpose string, ttl time.Duration) (string, error)
+	ValidateScopedToken(ctx context.Context, tokenString, purpose string) (*jwt.Token, error)
+	GenerateEmailVerificationToken(userID, email string, ttl time.Duration) (string, error)
+	AccessTokenTTL() time.Duration
+	RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID string) error
+	JWKS() types.JWKSResponse
+	ReloadKeys(cfg config.JWTConfig) error
 }
 
 type jwtUseCase struct {
-	secretKey string
-	issuer    string
+	keyRing          *keyRing
+	issuer           string
+	audience         []string
+	accessTokenTTL   time.Duration
+	leeway           time.Duration
+	parser           *jwt.Parser
+	revokedTokenRepo mysql.IRevokedTokenRepository
+
+	purgeMu   sync.Mutex
+	lastPurge time.Time
 }
 
+// jwtCustomClaim carries the user, the roles of access tokens and, for
+// anything but access tokens, the single purpose the token may be used for.
+// Email binds verification links to the address they were sent to.
 type jwtCustomClaim struct {
-	UserID string `json:"user_id"`
-	jwt.StandardClaims
+	UserID  string   `json:"user_id"`
+	Roles   []string `json:"roles,omitempty"`
+	Purpose string   `json:"purpose,omitempty"`
+	Email   string   `json:"email,omitempty"`
+	jwt.RegisteredClaims
 }
 
-func NewJWTUseCase() IJWTUseCase {
+func NewJWTUseCase(cfg config.JWTConfig, revokedTokenRepo mysql.IRevokedTokenRepository) (IJWTUseCase, error) {
+	ring, err := loadKeyRing(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT signing keys: %w", err)
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if cfg.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(cfg.Issuer))
+	}
+	if len(cfg.Audience) > 0 {
+		parserOptions = append(parserOptions, jwt.WithAudience(cfg.Audience...))
+	}
+
 	return &jwtUseCase{
-		secretKey: os.Getenv("JWT_SECRET_KEY"),
-		issuer:    os.Getenv("JWT_ISSUER"),
+		keyRing:          ring,
+		issuer:           cfg.Issuer,
+		audience:         cfg.Audience,
+		accessTokenTTL:   cfg.AccessTokenTTL,
+		leeway:           cfg.Leeway,
+		parser:           jwt.NewParser(parserOptions...),
+		revokedTokenRepo: revokedTokenRepo,
+	}, nil
+}
+
+func (j *jwtUseCase) GenerateToken(userID string, roles []string) (string, error) {
+	return j.sign(&jwtCustomClaim{UserID: userID, Roles: roles}, j.accessTokenTTL)
+}
+
+// GenerateScopedToken issues a token that only ValidateScopedToken with the
+// same purpose accepts. An empty purpose issues a regular access token.
+func (j *jwtUseCase) GenerateScopedToken(userID, purpose string, ttl time.Duration) (string, error) {
+	return j.sign(&jwtCustomClaim{UserID: userID, Purpose: purpose}, ttl)
+}
+
+// GenerateEmailVerificationToken issues a PurposeEmailVerification token whose
+// email claim must still match the account when the link is followed.
+func (j *jwtUseCase) GenerateEmailVerificationToken(userID, email string, ttl time.Duration) (string, error) {
+	return j.sign(&jwtCustomClaim{UserID: userID, Purpose: PurposeEmailVerification, Email: email}, ttl)
+}
+
+func (j *jwtUseCase) sign(claims *jwtCustomClaim, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   claims.UserID,
+		Audience:  j.audience,
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    j.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+
+	signer := j.keyRing.signer()
+	token := jwt.NewWithClaims(signer.method, claims)
+	token.Header["kid"] = signer.kid
+	return token.SignedString(signer.signKey)
+}
+
+func (j *jwtUseCase) ValidateToken(ctx context.Context, encodedToken string) (*jwt.Token, error) {
+	return j.ValidateScopedToken(ctx, encodedToken, "")
+}
+
+func (j *jwtUseCase) ValidateScopedToken(ctx context.Context, encodedToken, purpose string) (*jwt.Token, error) {
+	token, err := j.parser.Parse(encodedToken, j.verificationKey)
+	if err != nil {
+		return token, classifyError(err)
+	}
+
+	// Keeps e.g. an MFA challenge from being replayed as an access token.
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return token, ErrTokenInvalidClaims
+	}
+	if tokenPurpose, _ := claims["purpose"].(string); tokenPurpose != purpose {
+		return token, ErrTokenWrongPurpose
 	}
+
+	j.purgeExpired(ctx)
+	if err := j.checkRevocation(ctx, token, purpose); err != nil {
+		return token, err
+	}
+
+	return token, nil
+}
+
+// verificationKey resolves the key for a token by its kid header, and refuses
+// any token whose alg differs from the key's, so an HMAC token can never be
+// checked against a published public key.
+func (j *jwtUseCase) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keyRing.lookup(kid)
+	if !ok {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	return key.verifyKey, nil
 }
 
-func (j *jwtUseCase) GenerateToken(userID string) (string, error) {
-	claims := &jwtCustomClaim{
-		userID,
-		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
-			Issuer:    j.issuer,
-			IssuedAt:  time.Now().Unix(),
-		},
+// classifyError maps the parser's errors onto this package's sentinel errors,
+// so callers never depend on the JWT library directly.
+func classifyError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return ErrTokenBadSignature
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return ErrTokenNotYetValid
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return ErrTokenWrongAudience
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ErrTokenWrongIssuer
+	default:
+		return fmt.Errorf("%w: %v", ErrTokenInvalidClaims, err)
 	}
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.secretKey))
 }
 
-func (j *jwtUseCase) ValidateToken(encodedToken string) (*jwt.Token, error) {
-	token, err := jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
+func (j *jwtUseCase) JWKS() types.JWKSResponse {
+	keys := make([]types.JSONWebKey, 0)
+	for _, key := range j.keyRing.verificationKeys() {
+		if jwk, ok := key.jwk(); ok {
+			keys = append(keys, jwk)
 		}
-		return []byte(j.secretKey), nil
+	}
+	return types.JWKSResponse{Keys: keys}
+}
+
+// ReloadKeys re-reads the keys cfg points at and makes its signing key
+// current. Keys dropped from the configuration keep validating for one access
+// token lifetime, so no token issued before the rotation is rejected.
+func (j *jwtUseCase) ReloadKeys(cfg config.JWTConfig) error {
+	ring, err := loadKeyRing(cfg)
+	if err != nil {
+		return err
+	}
+
+	j.keyRing.replace(ring, j.accessTokenTTL+j.leeway)
+	return nil
+}
+
+func (j *jwtUseCase) AccessTokenTTL() time.Duration {
+	return j.accessTokenTTL
+}
+
+func (j *jwtUseCase) RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	j.purgeExpired(ctx)
+
+	if jti == "" {
+		return errors.New("token has no jti claim")
+	}
+
+	return j.revokedTokenRepo.Create(ctx, &domain.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
 	})
-	return token, err
+}
+
+func (j *jwtUseCase) RevokeUserTokens(ctx context.Context, userID string) error {
+	j.purgeExpired(ctx)
+
+	// iat only has whole seconds, so the cutoff is the start of the next
+	// second: every token issued up to now is revoked, at the cost of also
+	// revoking those issued in the rest of this second. The timestamp column
+	// stores it exactly, where a fractional time would be rounded.
+	cutoff := time.Now().Truncate(time.Second).Add(time.Second)
+	// Nothing issued before the cutoff can outlive one access token
+	// lifetime, so it can be purged once that window has passed.
+	return j.revokedTokenRepo.RevokeUser(ctx, &domain.UserTokenRevocation{
+		UserID:    userID,
+		RevokedAt: cutoff,
+		ExpiresAt: cutoff.Add(j.accessTokenTTL + j.leeway),
+	})
+}
+
+func (j *jwtUseCase) checkRevocation(ctx context.Context, token *jwt.Token, purpose string) error {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ErrTokenRevoked
+	}
+
+	// Tokens issued before jti existed cannot be revoked individually.
+	if jti, _ := claims["jti"].(string); jti != "" {
+		revoked, err := j.revokedTokenRepo.IsRevoked(ctx, jti)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+
+	// Revoking a user's sessions must not void a pending verification link,
+	// which is bound to the address it was sent to instead.
+	if purpose == PurposeEmailVerification {
+		return nil
+	}
+
+	userID, _ := claims["user_id"].(string)
+	revocation, err := j.revokedTokenRepo.FindUserRevocation(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if revocation != nil {
+		// RevokedAt is the first second whose tokens remain valid.
+		issuedAt, _ := claims["iat"].(float64)
+		if int64(issuedAt) < revocation.RevokedAt.Unix() {
+			return ErrTokenRevoked
+		}
+	}
+
+	return nil
+}
+
+// purgeExpired drops revocation entries for tokens that would already fail
+// the exp check, at most once per revocationPurgeEvery. Callers never wait
+// on a purge already running in another request.
+func (j *jwtUseCase) purgeExpired(ctx context.Context) {
+	if !j.purgeMu.TryLock() {
+		return
+	}
+	defer j.purgeMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(j.lastPurge) < revocationPurgeEvery {
+		return
+	}
+
+	// Tokens stay acceptable for the leeway after exp, so must their revocations.
+	if err := j.revokedTokenRepo.DeleteExpired(ctx, now.Add(-j.leeway)); err == nil {
+		j.lastPurge = now
+	}
 }
@@ -0,0 +1,127 @@
+package jwt_usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret-that-is-long-enough-for-hs256")
+
+// newTestKeyRing returns a ring signing with HS256 under the default kid that
+// also trusts an Ed25519 key "ed" and a retired HMAC key "old".
+func newTestKeyRing(t *testing.T) (*keyRing, ed25519.PublicKey) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, err := newAsymmetricSigningKey(jwt.SigningMethodEdDSA, "ed", privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey.signKey = nil
+
+	current := &signingKey{kid: defaultHMACKeyID, method: jwt.SigningMethodHS256, signKey: testSecret, verifyKey: testSecret}
+	retired := &signingKey{kid: "old", method: jwt.SigningMethodHS256, verifyKey: []byte("old-secret"), retireAt: time.Now().Add(-time.Minute)}
+
+	return &keyRing{
+		current: current,
+		keys:    map[string]*signingKey{current.kid: current, edKey.kid: edKey, retired.kid: retired},
+	}, publicKey
+}
+
+type verificationKeyTest struct {
+	name    string
+	kid     string
+	method  jwt.SigningMethod
+	wantKey interface{}
+}
+
+// testVerificationKey checks the key verificationKey picks for a token with
+// the given kid and alg. A nil wantKey expects the token to be refused.
+func testVerificationKey(t *testing.T, j *jwtUseCase, tests []verificationKeyTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &jwt.Token{Method: tt.method, Header: map[string]interface{}{"alg": tt.method.Alg()}}
+			if tt.kid != "" {
+				token.Header["kid"] = tt.kid
+			}
+
+			key, err := j.verificationKey(token)
+			if tt.wantKey == nil {
+				if !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+					t.Fatalf("verificationKey() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verificationKey() error = %v", err)
+			}
+
+			switch want := tt.wantKey.(type) {
+			case []byte:
+				if got, ok := key.([]byte); !ok || string(got) != string(want) {
+					t.Errorf("verificationKey() = %v, want the HMAC secret", key)
+				}
+			case ed25519.PublicKey:
+				if got, ok := key.(ed25519.PublicKey); !ok || !got.Equal(want) {
+					t.Errorf("verificationKey() = %v, want the Ed25519 public key", key)
+				}
+			}
+		})
+	}
+}
+
+func TestVerificationKeyBindsAlgorithm(t *testing.T) {
+	ring, publicKey := newTestKeyRing(t)
+
+	testVerificationKey(t, &jwtUseCase{keyRing: ring}, []verificationKeyTest{
+		{"HMAC key", defaultHMACKeyID, jwt.SigningMethodHS256, testSecret},
+		{"asymmetric key", "ed", jwt.SigningMethodEdDSA, publicKey},
+		{"HMAC alg with an asymmetric kid", "ed", jwt.SigningMethodHS256, nil},
+		{"asymmetric alg with an HMAC kid", defaultHMACKeyID, jwt.SigningMethodEdDSA, nil},
+		{"other HMAC alg for the same kid", defaultHMACKeyID, jwt.SigningMethodHS512, nil},
+		{"none alg", defaultHMACKeyID, jwt.SigningMethodNone, nil},
+	})
+}
+
+func TestVerificationKeySelectsKid(t *testing.T) {
+	ring, _ := newTestKeyRing(t)
+
+	testVerificationKey(t, &jwtUseCase{keyRing: ring}, []verificationKeyTest{
+		{"current kid", defaultHMACKeyID, jwt.SigningMethodHS256, testSecret},
+		{"no kid falls back to the default key", "", jwt.SigningMethodHS256, testSecret},
+		{"unknown kid", "missing", jwt.SigningMethodHS256, nil},
+		{"retired kid", "old", jwt.SigningMethodHS256, nil},
+	})
+}
+
+// An attacker who knows a published public key must not be able to use it as
+// an HMAC secret.
+func TestValidateTokenRejectsPublicKeyAsHMACSecret(t *testing.T) {
+	ring, publicKey := newTestKeyRing(t)
+	j := &jwtUseCase{keyRing: ring, parser: jwt.NewParser(jwt.WithExpirationRequired())}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": "00000000-0000-4000-8000-000000000000",
+		"exp":     time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = "ed"
+	forged, err := token.SignedString([]byte(publicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.ValidateToken(context.Background(), forged); !errors.Is(err, ErrTokenBadSignature) {
+		t.Fatalf("ValidateToken() error = %v, want %v", err, ErrTokenBadSignature)
+	}
+}
@@ -0,0 +1,169 @@
+package jwt_usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyRing holds the key new tokens are signed with and every key tokens are
+// still accepted from, indexed by kid.
+type keyRing struct {
+	mu      sync.RWMutex
+	current *signingKey
+	keys    map[string]*signingKey
+}
+
+// loadKeyRing reads the signing key plus the verification keys: PEM file
+// paths, optionally prefixed with "kid=", and previous HMAC secrets given as
+// "kid=secret".
+func loadKeyRing(cfg config.JWTConfig) (*keyRing, error) {
+	current, err := loadSigningKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*signingKey{current.kid: current}
+
+	for _, entry := range cfg.VerificationKeys {
+		kid, path := splitKeyEntry(entry)
+
+		key, err := readPEMKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		method, err := defaultMethodFor(key, current.method)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", path, err)
+		}
+
+		verifyKey, err := newAsymmetricSigningKey(method, kid, key)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", path, err)
+		}
+		verifyKey.signKey = nil
+
+		if err := addVerificationKey(keys, verifyKey); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entry := range cfg.PreviousSecretKeys {
+		kid, secret := splitKeyEntry(entry)
+		if kid == "" || secret == "" {
+			return nil, fmt.Errorf("JWT_PREVIOUS_SECRET_KEYS entries must be kid=secret")
+		}
+
+		method := current.method
+		if _, ok := method.(*jwt.SigningMethodHMAC); !ok {
+			method = jwt.SigningMethodHS256
+		}
+
+		if err := addVerificationKey(keys, &signingKey{kid: kid, method: method, verifyKey: []byte(secret)}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &keyRing{current: current, keys: keys}, nil
+}
+
+func addVerificationKey(keys map[string]*signingKey, key *signingKey) error {
+	if _, exists := keys[key.kid]; exists {
+		return fmt.Errorf("duplicate JWT key id %q", key.kid)
+	}
+	keys[key.kid] = key
+	return nil
+}
+
+// signer returns the key new tokens must be signed with.
+func (r *keyRing) signer() *signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// lookup returns the trusted key for kid. Tokens without a kid predate key
+// rotation and were signed with the original HMAC secret.
+func (r *keyRing) lookup(kid string) (*signingKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if kid == "" {
+		kid = defaultHMACKeyID
+	}
+
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, false
+	}
+
+	if !key.retireAt.IsZero() && time.Now().After(key.retireAt) {
+		return nil, false
+	}
+
+	return key, true
+}
+
+// verificationKeys returns every trusted key, current first, ordered by kid.
+func (r *keyRing) verificationKeys() []*signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]*signingKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		if key == r.current || (!key.retireAt.IsZero() && now.After(key.retireAt)) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].kid < keys[j].kid })
+
+	return append([]*signingKey{r.current}, keys...)
+}
+
+// replace swaps in a freshly loaded ring. Keys that disappear from the
+// configuration stay trusted for gracePeriod so tokens they signed can run
+// out their natural lifetime.
+func (r *keyRing) replace(next *keyRing, gracePeriod time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	retireAt := now.Add(gracePeriod)
+
+	for kid, key := range r.keys {
+		if _, kept := next.keys[kid]; kept {
+			continue
+		}
+		if !key.retireAt.IsZero() && now.After(key.retireAt) {
+			continue
+		}
+
+		retired := *key
+		retired.signKey = nil
+		if retired.retireAt.IsZero() || retired.retireAt.After(retireAt) {
+			retired.retireAt = retireAt
+		}
+		next.keys[kid] = &retired
+	}
+
+	r.current = next.current
+	r.keys = next.keys
+}
+
+// splitKeyEntry splits "kid=value" entries; entries without "=" have no kid.
+func splitKeyEntry(entry string) (string, string) {
+	kid, value, found := strings.Cut(entry, "=")
+	if !found {
+		return "", strings.TrimSpace(entry)
+	}
+	return strings.TrimSpace(kid), strings.TrimSpace(value)
+}
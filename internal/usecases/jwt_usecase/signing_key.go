@@ -0,0 +1,288 @@
+package jwt_usecase
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultHMACKeyID = "default"
+
+// signingKey pairs a JWT algorithm with the material used to sign and verify
+// tokens carrying its kid header. Verification-only keys have no signKey, and
+// keys demoted by a rotation stop being trusted at retireAt.
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	retireAt  time.Time
+}
+
+// loadSigningKey builds the signing key for cfg.SigningAlg. HMAC algorithms
+// use the secret key, or the contents of the secret key file when set; every
+// other algorithm reads a PEM encoded private key from the private key file.
+func loadSigningKey(cfg config.JWTConfig) (*signingKey, error) {
+	alg := cfg.SigningAlg
+	if alg == "" {
+		alg = jwt.SigningMethodHS256.Alg()
+	}
+	kid := cfg.KeyID
+
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", alg)
+	}
+
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		if kid == "" {
+			kid = defaultHMACKeyID
+		}
+		secret, err := loadSecret(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &signingKey{kid: kid, method: method, signKey: secret, verifyKey: secret}, nil
+	}
+
+	path := cfg.PrivateKeyFile
+	if path == "" {
+		return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", alg)
+	}
+
+	key, err := readPEMKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAsymmetricSigningKey(method, kid, key)
+}
+
+func loadSecret(cfg config.JWTConfig) ([]byte, error) {
+	secret := []byte(cfg.SecretKey)
+
+	if cfg.SecretKeyFile != "" {
+		data, err := os.ReadFile(cfg.SecretKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read JWT secret: %w", err)
+		}
+		secret = bytes.TrimSpace(data)
+	}
+
+	// An empty HMAC key would let anyone forge tokens.
+	if len(secret) == 0 {
+		return nil, errors.New("JWT secret is empty")
+	}
+
+	return secret, nil
+}
+
+// newAsymmetricSigningKey accepts either a private key, giving a key that can
+// sign, or a public key, giving a verification-only key.
+func newAsymmetricSigningKey(method jwt.SigningMethod, kid string, key interface{}) (*signingKey, error) {
+	signKey, publicKey := splitKey(key)
+	if publicKey == nil {
+		return nil, errors.New("unsupported key type")
+	}
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		if _, ok := method.(*jwt.SigningMethodRSA); !ok {
+			if _, ok := method.(*jwt.SigningMethodRSAPSS); !ok {
+				return nil, fmt.Errorf("RSA key cannot be used with %s", method.Alg())
+			}
+		}
+	case *ecdsa.PublicKey:
+		ecMethod, ok := method.(*jwt.SigningMethodECDSA)
+		if !ok || ecMethod.CurveBits != pub.Curve.Params().BitSize {
+			return nil, fmt.Errorf("%s key cannot be used with %s", pub.Curve.Params().Name, method.Alg())
+		}
+	case ed25519.PublicKey:
+		if method != jwt.SigningMethodEdDSA {
+			return nil, fmt.Errorf("Ed25519 key cannot be used with %s", method.Alg())
+		}
+	}
+
+	k := &signingKey{method: method, signKey: signKey, verifyKey: publicKey}
+
+	if kid == "" {
+		jwk, _ := k.jwk()
+		thumbprint, err := jwkThumbprint(jwk)
+		if err != nil {
+			return nil, err
+		}
+		kid = thumbprint
+	}
+	k.kid = kid
+
+	return k, nil
+}
+
+// splitKey returns the private half (nil for public keys) and the public half
+// of a parsed key.
+func splitKey(key interface{}) (crypto.PrivateKey, crypto.PublicKey) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return k, &k.PublicKey
+	case ed25519.PrivateKey:
+		return k, k.Public()
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return nil, k
+	default:
+		return nil, nil
+	}
+}
+
+// defaultMethodFor picks the algorithm for a verification key whose alg is
+// not configured, preferring preferred when the key type allows it.
+func defaultMethodFor(key interface{}, preferred jwt.SigningMethod) (jwt.SigningMethod, error) {
+	_, publicKey := splitKey(key)
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		switch preferred.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return preferred, nil
+		}
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve.Params().BitSize {
+		case 256:
+			return jwt.SigningMethodES256, nil
+		case 384:
+			return jwt.SigningMethodES384, nil
+		case 521:
+			return jwt.SigningMethodES512, nil
+		}
+		return nil, fmt.Errorf("unsupported curve %s", pub.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+}
+
+func readPEMKeyFile(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWT key: %w", err)
+	}
+
+	return parseKeyPEM(data)
+}
+
+func parseKeyPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("JWT key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// jwk returns the public JSON Web Key. Symmetric keys are never published.
+func (k *signingKey) jwk() (types.JSONWebKey, bool) {
+	jwk := types.JSONWebKey{Use: "sig", Alg: k.method.Alg(), Kid: k.kid}
+
+	switch key := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeBase64URL(key.N.Bytes())
+		jwk.E = encodeBase64URL(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = curveName(key.Curve)
+		jwk.X = encodeBase64URL(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeBase64URL(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeBase64URL(key)
+	default:
+		return jwk, false
+	}
+
+	return jwk, true
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint of a public key.
+func jwkThumbprint(jwk types.JSONWebKey) (string, error) {
+	var members interface{}
+
+	// Members must be serialized in lexicographic order, which encoding/json
+	// guarantees for map keys.
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	case "EC":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X, "y": jwk.Y}
+	case "OKP":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	default:
+		return "", fmt.Errorf("cannot compute thumbprint for key type %q", jwk.Kty)
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return encodeBase64URL(sum[:]), nil
+}
+
+func curveName(curve elliptic.Curve) string {
+	switch curve {
+	case elliptic.P256():
+		return "P-256"
+	case elliptic.P384():
+		return "P-384"
+	case elliptic.P521():
+		return "P-521"
+	default:
+		return curve.Params().Name
+	}
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,164 @@
+package lockout_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+)
+
+var (
+	ErrAccountLocked   = errors.New("account temporarily locked after too many failed login attempts")
+	ErrTooManyAttempts = errors.New("too many failed login attempts from this address")
+	ErrInvalidScope    = errors.New("scope must be user or ip")
+)
+
+// LockedError reports a refused login together with how long the caller has
+// to wait. It unwraps to ErrAccountLocked or ErrTooManyAttempts.
+type LockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedError) Unwrap() error {
+	return e.Err
+}
+
+type ILockoutUseCase interface {
+	CheckIP(ctx context.Context, ip string) error
+	CheckUser(ctx context.Context, userID string) error
+	RegisterFailure(ctx context.Context, userID, ip string) error
+	RegisterSuccess(ctx context.Context, userID string) error
+	GetActiveLockouts(ctx context.Context, offset, limit int) (*[]domain.LoginLockout, int64, error)
+	GetLockout(ctx context.Context, scope, subject string) (*domain.LoginLockout, error)
+	ClearLockout(ctx context.Context, scope, subject string) error
+}
+
+type lockoutUseCase struct {
+	lockoutRepo     mysql.ILoginLockoutRepository
+	maxUserAttempts int
+	maxIPAttempts   int
+	lockoutBase     time.Duration
+	lockoutMax      time.Duration
+	failureWindow   time.Duration
+}
+
+func NewLockoutUseCase(cfg config.LockoutConfig, lockoutRepo mysql.ILoginLockoutRepository) ILockoutUseCase {
+	return &lockoutUseCase{
+		lockoutRepo:     lockoutRepo,
+		maxUserAttempts: cfg.MaxFailedAttempts,
+		maxIPAttempts:   cfg.MaxFailedAttemptsPerIP,
+		lockoutBase:     cfg.Base,
+		lockoutMax:      cfg.Max,
+		failureWindow:   cfg.FailureWindow,
+	}
+}
+
+func (l *lockoutUseCase) CheckIP(ctx context.Context, ip string) error {
+	return l.check(ctx, domain.LockoutScopeIP, ip, ErrTooManyAttempts)
+}
+
+func (l *lockoutUseCase) CheckUser(ctx context.Context, userID string) error {
+	return l.check(ctx, domain.LockoutScopeUser, userID, ErrAccountLocked)
+}
+
+// RegisterFailure counts a failed login against the client IP and, when the
+// email matched an account, against that user. userID may be empty.
+func (l *lockoutUseCase) RegisterFailure(ctx context.Context, userID, ip string) error {
+	if _, err := l.lockoutRepo.Modify(ctx, domain.LockoutScopeIP, ip, l.recordFailure(l.maxIPAttempts)); err != nil {
+		return err
+	}
+
+	if userID == "" {
+		return nil
+	}
+
+	_, err := l.lockoutRepo.Modify(ctx, domain.LockoutScopeUser, userID, l.recordFailure(l.maxUserAttempts))
+	return err
+}
+
+// RegisterSuccess resets the user's counters. The IP counters only decay with
+// time, so an attacker can't reset them by logging into their own account.
+func (l *lockoutUseCase) RegisterSuccess(ctx context.Context, userID string) error {
+	return l.lockoutRepo.Delete(ctx, domain.LockoutScopeUser, userID)
+}
+
+func (l *lockoutUseCase) GetActiveLockouts(ctx context.Context, offset, limit int) (*[]domain.LoginLockout, int64, error) {
+	return l.lockoutRepo.FindActive(ctx, time.Now(), offset, limit)
+}
+
+func (l *lockoutUseCase) GetLockout(ctx context.Context, scope, subject string) (*domain.LoginLockout, error) {
+	if err := validateScope(scope); err != nil {
+		return nil, err
+	}
+	return l.lockoutRepo.Find(ctx, scope, subject)
+}
+
+func (l *lockoutUseCase) ClearLockout(ctx context.Context, scope, subject string) error {
+	if err := validateScope(scope); err != nil {
+		return err
+	}
+	return l.lockoutRepo.Delete(ctx, scope, subject)
+}
+
+func (l *lockoutUseCase) check(ctx context.Context, scope, subject string, lockedErr error) error {
+	lockout, err := l.lockoutRepo.Find(ctx, scope, subject)
+	if err != nil || lockout == nil || lockout.LockedUntil == nil {
+		return err
+	}
+
+	if wait := time.Until(*lockout.LockedUntil); wait > 0 {
+		return &LockedError{Err: lockedErr, RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// recordFailure returns the update for one more failed attempt. Reaching
+// maxAttempts locks the subject for lockoutBase doubled for every lockout
+// since the subject last behaved, capped at lockoutMax.
+func (l *lockoutUseCase) recordFailure(maxAttempts int) func(*domain.LoginLockout) {
+	return func(lockout *domain.LoginLockout) {
+		now := time.Now()
+		sinceLastFailure := now.Sub(lockout.LastFailureAt)
+
+		if sinceLastFailure > l.failureWindow {
+			lockout.FailedAttempts = 0
+		}
+		if sinceLastFailure > l.lockoutMax+l.failureWindow {
+			lockout.Lockouts = 0
+		}
+
+		lockout.FailedAttempts++
+		lockout.LastFailureAt = now
+
+		if lockout.FailedAttempts < maxAttempts {
+			return
+		}
+
+		duration := l.lockoutBase << min(lockout.Lockouts, 30)
+		if duration <= 0 || duration > l.lockoutMax {
+			duration = l.lockoutMax
+		}
+
+		lockedUntil := now.Add(duration)
+		lockout.LockedUntil = &lockedUntil
+		lockout.Lockouts++
+		lockout.FailedAttempts = 0
+	}
+}
+
+func validateScope(scope string) error {
+	if scope != domain.LockoutScopeUser && scope != domain.LockoutScopeIP {
+		return ErrInvalidScope
+	}
+	return nil
+}
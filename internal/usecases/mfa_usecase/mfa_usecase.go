@@ -0,0 +1,226 @@
+package mfa_usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/types"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/totp"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10
+	// Accept the previous and next code too, to tolerate clock drift on phones.
+	allowedStepSkew = 1
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor authentication enrollment not started")
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrInvalidMFACode    = errors.New("invalid two-factor authentication code")
+)
+
+type IMFAUseCase interface {
+	Enroll(ctx context.Context, userID string) (*types.MFAEnrollmentResponse, error)
+	ConfirmEnrollment(ctx context.Context, userID, code string) (*types.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID, code string) error
+	VerifyCode(ctx context.Context, user *domain.User, code string) error
+}
+
+type mfaUseCase struct {
+	userRepo         mysql.IUserRepository
+	recoveryCodeRepo mysql.IRecoveryCodeRepository
+	issuer           string
+}
+
+func NewMFAUseCase(cfg config.MFAConfig, userRepo mysql.IUserRepository, recoveryCodeRepo mysql.IRecoveryCodeRepository) IMFAUseCase {
+	return &mfaUseCase{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		issuer:           cfg.Issuer,
+	}
+}
+
+// Enroll stores a fresh secret that stays inactive until ConfirmEnrollment
+// proves the authenticator app produces matching codes.
+func (m *mfaUseCase) Enroll(ctx context.Context, userID string) (*types.MFAEnrollmentResponse, error) {
+	user, err := m.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastUsedStep = 0
+	if err := m.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &types.MFAEnrollmentResponse{
+		Secret: secret,
+		URI:    totp.URI(m.issuer, user.Email, secret),
+	}, nil
+}
+
+func (m *mfaUseCase) ConfirmEnrollment(ctx context.Context, userID, code string) (*types.MFARecoveryCodesResponse, error) {
+	user, err := m.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	if err := m.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := m.generateRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	if err := m.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &types.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (m *mfaUseCase) Disable(ctx context.Context, userID, code string) error {
+	user, err := m.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if err := m.VerifyCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastUsedStep = 0
+	if err := m.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return m.recoveryCodeRepo.DeleteForUser(ctx, user.ID)
+}
+
+// VerifyCode accepts either a current TOTP code or an unused recovery code,
+// consuming the latter.
+func (m *mfaUseCase) VerifyCode(ctx context.Context, user *domain.User, code string) error {
+	if !user.TOTPEnabled {
+		return ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return m.verifyTOTP(ctx, user, code)
+	}
+
+	return m.useRecoveryCode(ctx, user.ID, code)
+}
+
+func (m *mfaUseCase) verifyTOTP(ctx context.Context, user *domain.User, code string) error {
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), allowedStepSkew)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	// A code seen once must not be accepted again, even within its window.
+	accepted, err := m.userRepo.UpdateTOTPLastUsedStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return ErrInvalidMFACode
+	}
+	user.TOTPLastUsedStep = step
+
+	return nil
+}
+
+func (m *mfaUseCase) useRecoveryCode(ctx context.Context, userID, code string) error {
+	stored, err := m.recoveryCodeRepo.FindUnusedByHash(ctx, userID, helpers.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+
+	used, err := m.recoveryCodeRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (m *mfaUseCase) generateRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+
+		code := normalizeRecoveryCode(recoveryCodeEncoding.EncodeToString(raw))
+		codes = append(codes, code[:len(code)/2]+"-"+code[len(code)/2:])
+		records = append(records, domain.RecoveryCode{
+			ID:       uuid.NewString(),
+			UserID:   userID,
+			CodeHash: helpers.HashToken(code),
+		})
+	}
+
+	if err := m.recoveryCodeRepo.ReplaceForUser(ctx, userID, records); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode lets users type codes with or without the dash and
+// in any letter case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
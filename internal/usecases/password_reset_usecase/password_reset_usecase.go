@@ -0,0 +1,171 @@
+package password_reset_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/mailer"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/throttle"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	resetTokenSize = 32
+	// minPasswordLength matches the rule the signup endpoint enforces.
+	minPasswordLength = 8
+)
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	ErrPasswordTooShort  = fmt.Errorf("password must have at least %d characters", minPasswordLength)
+)
+
+// ThrottledError reports a reset request refused because the client IP asked
+// too often, together with how long it has to wait.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many password reset requests from this address, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+type IPasswordResetUseCase interface {
+	ForgotPassword(ctx context.Context, email, clientIP string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type passwordResetUseCase struct {
+	userRepo         mysql.IUserRepository
+	resetTokenRepo   mysql.IPasswordResetTokenRepository
+	userUseCase      user_usecase.IUserUseCase
+	mailer           *mailer.Dispatcher
+	tokenTTL         time.Duration
+	resetURL         string
+	requestsPerIP    *throttle.Window
+	requestsPerEmail *throttle.Window
+}
+
+func NewPasswordResetUseCase(cfg config.PasswordResetConfig, userRepo mysql.IUserRepository, resetTokenRepo mysql.IPasswordResetTokenRepository, userUseCase user_usecase.IUserUseCase, mail *mailer.Dispatcher) IPasswordResetUseCase {
+	return &passwordResetUseCase{
+		userRepo:         userRepo,
+		resetTokenRepo:   resetTokenRepo,
+		userUseCase:      userUseCase,
+		mailer:           mail,
+		tokenTTL:         cfg.TokenTTL,
+		resetURL:         cfg.URL,
+		requestsPerIP:    throttle.NewWindow(cfg.MaxPerIP, cfg.ThrottleWindow),
+		requestsPerEmail: throttle.NewWindow(1, cfg.Cooldown),
+	}
+}
+
+// ForgotPassword emails a reset link when the address belongs to an active
+// account. It succeeds either way so callers can't probe for accounts, and
+// also when the address got a link less than cooldown ago. Only clients going
+// over maxPerIP requests per throttleWindow get a ThrottledError.
+func (p *passwordResetUseCase) ForgotPassword(ctx context.Context, email, clientIP string) error {
+	if ok, retryAfter := p.requestsPerIP.Allow(clientIP); !ok {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	if ok, _ := p.requestsPerEmail.Allow(strings.ToLower(email)); !ok {
+		return nil
+	}
+
+	user, err := p.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the most recent link is ever valid.
+	if err := p.resetTokenRepo.DeleteForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, err := helpers.GenerateRandomToken(resetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	err = p.resetTokenRepo.Create(ctx, &domain.PasswordResetToken{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: time.Now().Add(p.tokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+			user.FirstName, p.tokenTTL, p.resetLink(token)),
+	}
+
+	// Sent in the background so response times don't reveal whether the
+	// account exists.
+	p.mailer.Dispatch(msg)
+
+	return nil
+}
+
+// ResetPassword sets newPassword for the owner of token. A password that is
+// too short is refused before the token is consumed.
+func (p *passwordResetUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < minPasswordLength {
+		return ErrPasswordTooShort
+	}
+
+	stored, err := p.resetTokenRepo.FindByHash(ctx, helpers.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	used, err := p.resetTokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	if err := p.userUseCase.SetPassword(ctx, stored.UserID, newPassword); err != nil {
+		return err
+	}
+
+	return p.resetTokenRepo.DeleteForUser(ctx, stored.UserID)
+}
+
+func (p *passwordResetUseCase) resetLink(token string) string {
+	link, err := url.Parse(p.resetURL)
+	if err != nil {
+		return p.resetURL + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
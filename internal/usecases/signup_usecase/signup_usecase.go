@@ -0,0 +1,228 @@
+package signup_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/infrastructure/mailer"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/user_usecase"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const invitationTokenSize = 32
+
+var (
+	ErrSignupDisabled        = errors.New("self-registration is disabled")
+	ErrInvalidInvitation     = errors.New("invalid or expired invitation")
+	ErrEmailDomainNotAllowed = user_usecase.ErrEmailDomainNotAllowed
+)
+
+// ThrottledError reports a signup refused because the client IP signed up
+// too often, together with how long it has to wait.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many signups from this address, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+type ISignupUseCase interface {
+	Signup(ctx context.Context, user *domain.User, invitationCode, clientIP string) error
+	Invite(ctx context.Context, email, invitedBy string) (*domain.Invitation, string, error)
+}
+
+type signupUseCase struct {
+	userUseCase    user_usecase.IUserUseCase
+	invitationRepo mysql.IInvitationRepository
+	transactor     mysql.ITransactor
+	mailer         *mailer.Dispatcher
+	policy         string
+	invitationTTL  time.Duration
+	signupURL      string
+	maxPerIP       int
+	throttleWindow time.Duration
+
+	mu       sync.Mutex
+	attempts map[string]*signupWindow
+}
+
+type signupWindow struct {
+	start time.Time
+	count int
+}
+
+// NewSignupUseCase builds the use case for cfg.Policy. The domain policy
+// is checked by userUseCase, which applies it to email changes as well.
+func NewSignupUseCase(cfg config.SignupConfig, userUseCase user_usecase.IUserUseCase, invitationRepo mysql.IInvitationRepository, transactor mysql.ITransactor, mail *mailer.Dispatcher) ISignupUseCase {
+	return &signupUseCase{
+		userUseCase:    userUseCase,
+		invitationRepo: invitationRepo,
+		transactor:     transactor,
+		mailer:         mail,
+		policy:         cfg.Policy,
+		invitationTTL:  cfg.InvitationTTL,
+		signupURL:      cfg.URL,
+		maxPerIP:       cfg.MaxPerIP,
+		throttleWindow: cfg.ThrottleWindow,
+		attempts:       make(map[string]*signupWindow),
+	}
+}
+
+// Signup registers a regular user under the configured policy. An accepted
+// invitation proves the address, so those accounts start out verified.
+func (s *signupUseCase) Signup(ctx context.Context, user *domain.User, invitationCode, clientIP string) error {
+	if s.policy == config.SignupPolicyDisabled {
+		return ErrSignupDisabled
+	}
+
+	if err := s.allowSignup(clientIP); err != nil {
+		return err
+	}
+
+	user.Active = true
+	if user.FullName == "" {
+		user.FullName = user.FirstName + " " + user.LastName
+	}
+
+	switch s.policy {
+	case config.SignupPolicyDomain:
+		if err := s.userUseCase.CheckEmailDomain(user.Email); err != nil {
+			return err
+		}
+	case config.SignupPolicyInviteOnly:
+		invitation, err := s.findInvitation(ctx, user.Email, invitationCode)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+
+		// Consume the invitation together with the account, so a failed
+		// registration leaves it usable
+		return s.transactor.InTx(ctx, func(ctx context.Context) error {
+			if err := s.userUseCase.Register(ctx, user); err != nil {
+				return err
+			}
+			return s.consumeInvitation(ctx, invitation)
+		})
+	}
+
+	return s.userUseCase.Register(ctx, user)
+}
+
+// Invite creates an invitation for email and mails the signup link. The code
+// is returned as well so it can be handed over another way.
+func (s *signupUseCase) Invite(ctx context.Context, email, invitedBy string) (*domain.Invitation, string, error) {
+	code, err := helpers.GenerateRandomToken(invitationTokenSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	invitation := &domain.Invitation{
+		ID:        uuid.NewString(),
+		Email:     email,
+		TokenHash: helpers.HashToken(code),
+		InvitedBy: invitedBy,
+		ExpiresAt: time.Now().Add(s.invitationTTL),
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, "", err
+	}
+
+	msg := mailer.Message{
+		To:      []string{email},
+		Subject: "You have been invited",
+		Body: fmt.Sprintf("Hello,\n\nYou have been invited to create an account. Use the link below to sign up; it expires in %s.\n\n%s\n",
+			s.invitationTTL, s.invitationLink(code)),
+	}
+
+	s.mailer.Dispatch(msg)
+
+	return invitation, code, nil
+}
+
+// findInvitation returns the pending invitation of email matching code.
+func (s *signupUseCase) findInvitation(ctx context.Context, email, code string) (*domain.Invitation, error) {
+	if code == "" {
+		return nil, ErrInvalidInvitation
+	}
+
+	invitation, err := s.invitationRepo.FindByHash(ctx, helpers.HashToken(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	if invitation.UsedAt != nil || time.Now().After(invitation.ExpiresAt) || !strings.EqualFold(invitation.Email, email) {
+		return nil, ErrInvalidInvitation
+	}
+
+	return invitation, nil
+}
+
+// consumeInvitation marks invitation used unless a concurrent signup got
+// there first, in which case the caller's transaction must roll back.
+func (s *signupUseCase) consumeInvitation(ctx context.Context, invitation *domain.Invitation) error {
+	marked, err := s.invitationRepo.MarkUsed(ctx, invitation.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrInvalidInvitation
+	}
+	return nil
+}
+
+// allowSignup admits at most maxPerIP signup attempts per client IP in each
+// throttleWindow. Failed attempts count too, so guessing invitation codes or
+// probing for registered addresses is throttled as well.
+func (s *signupUseCase) allowSignup(clientIP string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for ip, window := range s.attempts {
+		if now.Sub(window.start) >= s.throttleWindow {
+			delete(s.attempts, ip)
+		}
+	}
+
+	window, ok := s.attempts[clientIP]
+	if !ok {
+		window = &signupWindow{start: now}
+		s.attempts[clientIP] = window
+	}
+
+	if window.count >= s.maxPerIP {
+		return &ThrottledError{RetryAfter: window.start.Add(s.throttleWindow).Sub(now)}
+	}
+	window.count++
+
+	return nil
+}
+
+func (s *signupUseCase) invitationLink(code string) string {
+	link, err := url.Parse(s.signupURL)
+	if err != nil {
+		return s.signupURL + "?invitation_code=" + url.QueryEscape(code)
+	}
+
+	query := link.Query()
+	query.Set("invitation_code", code)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
@@ -1,64 +1,139 @@
 package user_usecase
 
 import (
+	"context"
 	"errors"
+	"strings"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/config"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/domain"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/helpers"
 	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/repositories/mysql"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/email_verification_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/internal/usecases/jwt_usecase"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/metrics"
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/tracing"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type IUserUseCase interface {
-	Register(user *domain.User) error
-	GetUser(id string) (*domain.User, error)
-	GetAllUsers(offset, limit int) (*[]domain.User, int64, error)
-	UpdateUser(user *domain.User) error
-	DeleteUser(id string) error
-	ResetPassword(userID, oldPassword, newPassword string) error
+	Register(ctx context.Context, user *domain.User) error
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetAllUsers(ctx context.Context, offset, limit int) (*[]domain.User, int64, error)
+	UpdateUser(ctx context.Context, user *domain.User) error
+	DeleteUser(ctx context.Context, id string) error
+	DisableUser(ctx context.Context, id string) error
+	ResetPassword(ctx context.Context, userID, oldPassword, newPassword string) error
+	SetPassword(ctx context.Context, userID, newPassword string) error
+	GetUserRoles(ctx context.Context, userID string) ([]string, error)
+	AssignRoles(ctx context.Context, userID string, roles []string) error
+	CheckEmailDomain(email string) error
 }
 
-var ErrEmailAlreadyRegistered = errors.New("email already registered")
+var (
+	ErrEmailAlreadyRegistered = errors.New("email already registered")
+	ErrRoleNotFound           = errors.New("role not found")
+	ErrEmailDomainNotAllowed  = errors.New("email domain is not allowed")
+	ErrInvalidOldPassword     = errors.New("invalid old password")
+)
 
 type userUseCase struct {
-	userRepo mysql.IUserRepository
+	userRepo                 mysql.IUserRepository
+	refreshTokenRepo         mysql.IRefreshTokenRepository
+	roleRepo                 mysql.IRoleRepository
+	jwtUseCase               jwt_usecase.IJWTUseCase
+	emailVerificationUseCase email_verification_usecase.IEmailVerificationUseCase
+	metrics                  *metrics.Metrics
+	allowedDomains           map[string]struct{}
 }
 
-func NewUserUseCase(userRepo mysql.IUserRepository) IUserUseCase {
-	return &userUseCase{userRepo: userRepo}
+// NewUserUseCase builds the user use case. Under the domain signup policy,
+// users can only sign up with or change to an address of cfg.AllowedDomains.
+func NewUserUseCase(cfg config.SignupConfig, userRepo mysql.IUserRepository, refreshTokenRepo mysql.IRefreshTokenRepository, roleRepo mysql.IRoleRepository, jwtUseCase jwt_usecase.IJWTUseCase, emailVerificationUseCase email_verification_usecase.IEmailVerificationUseCase, appMetrics *metrics.Metrics) IUserUseCase {
+	var allowedDomains map[string]struct{}
+	if cfg.Policy == config.SignupPolicyDomain {
+		allowedDomains = make(map[string]struct{})
+		for _, domainName := range cfg.AllowedDomains {
+			allowedDomains[strings.ToLower(domainName)] = struct{}{}
+		}
+	}
+
+	return &userUseCase{
+		userRepo:                 userRepo,
+		refreshTokenRepo:         refreshTokenRepo,
+		roleRepo:                 roleRepo,
+		jwtUseCase:               jwtUseCase,
+		emailVerificationUseCase: emailVerificationUseCase,
+		metrics:                  appMetrics,
+		allowedDomains:           allowedDomains,
+	}
 }
 
-func (uc *userUseCase) Register(user *domain.User) error {
-	_, err := uc.userRepo.FindByEmail(user.Email)
+func (uc *userUseCase) Register(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.Register")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = uc.userRepo.FindByEmail(ctx, user.Email)
 	if err == nil {
 		return ErrEmailAlreadyRegistered
 	}
 
 	user.ID = uuid.NewString()
 
+	_, hashSpan := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashSpan.End()
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
 
-	return uc.userRepo.Create(user)
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	if err := uc.roleRepo.SetUserRoles(ctx, user.ID, []string{domain.RoleUser}); err != nil {
+		return err
+	}
+
+	// Not counted when an enclosing transaction rolls back
+	mysql.AfterCommit(ctx, uc.metrics.ObserveUserRegistered)
+
+	return uc.emailVerificationUseCase.SendVerification(ctx, user)
 }
 
-func (uc *userUseCase) GetUser(id string) (*domain.User, error) {
+func (uc *userUseCase) GetUser(ctx context.Context, id string) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.GetUser")
+	defer func() { tracing.End(span, err) }()
+
 	if err := helpers.IsValidUUIDv4(id); err != nil {
 		return nil, err
 	}
-	return uc.userRepo.FindByID(id)
+	return uc.userRepo.FindByID(ctx, id)
 }
 
-func (uc *userUseCase) GetAllUsers(offset, limit int) (*[]domain.User, int64, error) {
-	return uc.userRepo.FindAll(offset, limit)
+func (uc *userUseCase) GetUserByEmail(ctx context.Context, email string) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.GetUserByEmail")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.userRepo.FindByEmail(ctx, email)
 }
 
-func (uc *userUseCase) UpdateUser(user *domain.User) error {
-	userFind, err := uc.userRepo.FindByID(user.ID)
+func (uc *userUseCase) GetAllUsers(ctx context.Context, offset, limit int) (_ *[]domain.User, _ int64, err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.GetAllUsers")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.userRepo.FindAll(ctx, offset, limit)
+}
+
+func (uc *userUseCase) UpdateUser(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.UpdateUser")
+	defer func() { tracing.End(span, err) }()
+
+	userFind, err := uc.userRepo.FindByID(ctx, user.ID)
 	if err != nil {
 		return err
 	}
@@ -66,20 +141,103 @@ func (uc *userUseCase) UpdateUser(user *domain.User) error {
 	user.CreatedAt = userFind.CreatedAt
 	user.Password = userFind.Password
 	user.Active = true
+	user.TOTPSecret = userFind.TOTPSecret
+	user.TOTPEnabled = userFind.TOTPEnabled
+	user.TOTPLastUsedStep = userFind.TOTPLastUsedStep
+
+	// A new address must be allowed, free and has to be verified again.
+	emailChanged := user.Email != userFind.Email
+	if emailChanged {
+		if err := uc.CheckEmailDomain(user.Email); err != nil {
+			return err
+		}
+		if _, err := uc.userRepo.FindByEmail(ctx, user.Email); err == nil {
+			return ErrEmailAlreadyRegistered
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		user.EmailVerifiedAt = nil
+	} else {
+		user.EmailVerifiedAt = userFind.EmailVerifiedAt
+	}
 
 	if user.FullName == "" {
 		user.FullName = user.FirstName + " " + user.LastName
 	}
 
-	return uc.userRepo.Update(user)
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if emailChanged {
+		return uc.emailVerificationUseCase.SendVerification(ctx, user)
+	}
+	return nil
 }
 
-func (uc *userUseCase) DeleteUser(id string) error {
-	return uc.userRepo.Delete(id)
+func (uc *userUseCase) DeleteUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.DeleteUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.roleRepo.DeleteForUser(ctx, id); err != nil {
+		return err
+	}
+
+	return uc.revokeAllTokens(ctx, id)
 }
 
-func (uc *userUseCase) ResetPassword(userID, oldPassword, newPassword string) error {
-	user, err := uc.userRepo.FindByID(userID)
+// DisableUser deactivates the account without deleting it and ends every
+// session it holds.
+func (uc *userUseCase) DisableUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.DisableUser")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	user.Active = false
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return uc.revokeAllTokens(ctx, id)
+}
+
+func (uc *userUseCase) ResetPassword(ctx context.Context, userID, oldPassword, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	_, compareSpan := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword))
+	compareSpan.End()
+	if err != nil {
+		return ErrInvalidOldPassword
+	}
+
+	return uc.updatePassword(ctx, user, newPassword)
+}
+
+// SetPassword replaces the password without checking the current one, for
+// flows that already proved the caller's identity another way.
+func (uc *userUseCase) SetPassword(ctx context.Context, userID, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.SetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("user not found")
@@ -87,19 +245,77 @@ func (uc *userUseCase) ResetPassword(userID, oldPassword, newPassword string) er
 		return err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
-		return errors.New("invalid old password")
+	return uc.updatePassword(ctx, user, newPassword)
+}
+
+func (uc *userUseCase) GetUserRoles(ctx context.Context, userID string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.GetUserRoles")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
 	}
+	return uc.roleRepo.FindRoleNamesByUserID(ctx, userID)
+}
+
+// AssignRoles replaces the user's roles. Access tokens carry the roles they
+// were issued with, so they are revoked and the next refresh picks up the
+// new roles without ending the user's sessions.
+func (uc *userUseCase) AssignRoles(ctx context.Context, userID string, roles []string) (err error) {
+	ctx, span := tracing.Start(ctx, "userUseCase.AssignRoles")
+	defer func() { tracing.End(span, err) }()
 
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := uc.roleRepo.SetUserRoles(ctx, userID, roles); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	return uc.jwtUseCase.RevokeUserTokens(ctx, userID)
+}
+
+func (uc *userUseCase) updatePassword(ctx context.Context, user *domain.User, newPassword string) error {
+	_, hashSpan := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashSpan.End()
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
 
-	if err := uc.userRepo.Update(user); err != nil {
+	if err := uc.userRepo.Update(ctx, user); err != nil {
 		return err
 	}
 
+	return uc.revokeAllTokens(ctx, user.ID)
+}
+
+func (uc *userUseCase) revokeAllTokens(ctx context.Context, userID string) error {
+	if err := uc.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.jwtUseCase.RevokeUserTokens(ctx, userID)
+}
+
+// CheckEmailDomain returns ErrEmailDomainNotAllowed when the domain signup
+// policy is in force and email is outside the allowed domains.
+func (uc *userUseCase) CheckEmailDomain(email string) error {
+	if uc.allowedDomains == nil {
+		return nil
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ErrEmailDomainNotAllowed
+	}
+	if _, allowed := uc.allowedDomains[strings.ToLower(email[at+1:])]; !allowed {
+		return ErrEmailDomainNotAllowed
+	}
 	return nil
 }
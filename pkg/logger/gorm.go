@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Casagrande-Lucas/golang-clean-architecture/pkg/requestid"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger sends GORM's logs to slog. Every query is logged at debug
+// level, slow queries at warn and failed ones at error; a missing record is
+// an expected outcome and not treated as a failure.
+type GormLogger struct {
+	Logger        *slog.Logger
+	SlowThreshold time.Duration
+	level         gormlogger.LogLevel
+}
+
+func NewGormLogger(l *slog.Logger, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{Logger: l, SlowThreshold: slowThreshold, level: gormlogger.Info}
+}
+
+func (g *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *g
+	clone.level = level
+	return &clone
+}
+
+func (g *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if g.level >= gormlogger.Info {
+		g.logger(ctx).InfoContext(ctx, msg, "args", args)
+	}
+}
+
+func (g *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if g.level >= gormlogger.Warn {
+		g.logger(ctx).WarnContext(ctx, msg, "args", args)
+	}
+}
+
+func (g *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if g.level >= gormlogger.Error {
+		g.logger(ctx).ErrorContext(ctx, msg, "args", args)
+	}
+}
+
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.level <= gormlogger.Silent {
+		return
+	}
+
+	l := g.logger(ctx)
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gormlogger.ErrRecordNotFound) && g.level >= gormlogger.Error:
+		sql, rows := fc()
+		l.ErrorContext(ctx, "query failed", "error", err, "sql", sql, "rows", rows, "elapsed", elapsed)
+	case g.SlowThreshold > 0 && elapsed > g.SlowThreshold && g.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	case l.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		l.DebugContext(ctx, "query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
+
+// logger prefers the request-scoped logger so queries carry the request ID.
+func (g *GormLogger) logger(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		return g.Logger.With("request_id", requestID)
+	}
+	return g.Logger
+}
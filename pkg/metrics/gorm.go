@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startedAtKey = "metrics:started_at"
+
+// GormPlugin times every query GORM runs and counts the failed ones.
+type GormPlugin struct {
+	metrics *Metrics
+}
+
+func NewGormPlugin(m *Metrics) *GormPlugin {
+	return &GormPlugin{metrics: m}
+}
+
+func (p *GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	register := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("*").Register, db.Callback().Create().After("*").Register},
+		{"query", db.Callback().Query().Before("*").Register, db.Callback().Query().After("*").Register},
+		{"update", db.Callback().Update().Before("*").Register, db.Callback().Update().After("*").Register},
+		{"delete", db.Callback().Delete().Before("*").Register, db.Callback().Delete().After("*").Register},
+		{"row", db.Callback().Row().Before("*").Register, db.Callback().Row().After("*").Register},
+		{"raw", db.Callback().Raw().Before("*").Register, db.Callback().Raw().After("*").Register},
+	}
+
+	for _, r := range register {
+		if err := r.before("metrics:before_"+r.operation, p.before); err != nil {
+			return err
+		}
+		if err := r.after("metrics:after_"+r.operation, p.after(r.operation)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *GormPlugin) before(db *gorm.DB) {
+	db.InstanceSet(startedAtKey, time.Now())
+}
+
+func (p *GormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startedAtKey)
+		if !ok {
+			return
+		}
+		startedAt, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		p.metrics.dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(startedAt).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			p.metrics.dbQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
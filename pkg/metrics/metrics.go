@@ -0,0 +1,115 @@
+// Package metrics collects the Prometheus metrics of the application: HTTP
+// traffic, database queries and connection pool, and business events.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Login outcomes counted by ObserveLogin.
+const (
+	LoginSucceeded = "succeeded"
+	LoginFailed    = "failed"
+	LoginLocked    = "locked"
+)
+
+// Metrics owns its own registry, so several instances of the application can
+// live in one process without clashing on metric names.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	httpInFlight    prometheus.Gauge
+	dbQueryDuration *prometheus.HistogramVec
+	dbQueryErrors   *prometheus.CounterVec
+	logins          *prometheus.CounterVec
+	usersRegistered prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests handled, by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time spent handling HTTP requests, by method, route template and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests currently being handled.",
+		}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Time spent in database queries, by operation and table.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		dbQueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "Database queries that failed, by operation and table. A missing record is not an error.",
+		}, []string{"operation", "table"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_logins_total",
+			Help: "Login attempts, by result.",
+		}, []string{"result"}),
+		usersRegistered: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "users_registered_total",
+			Help: "Users registered.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.httpInFlight,
+		m.dbQueryDuration,
+		m.dbQueryErrors,
+		m.logins,
+		m.usersRegistered,
+	)
+
+	// Start at zero so rate() sees the first event.
+	for _, result := range []string{LoginSucceeded, LoginFailed, LoginLocked} {
+		m.logins.WithLabelValues(result)
+	}
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDBStats exports the sql.DBStats of the pool as gauges labelled
+// with dbName.
+func (m *Metrics) RegisterDBStats(dbName string, db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// ObserveLogin counts a login attempt. Methods on a nil *Metrics do nothing,
+// for callers such as the seeder that run without metrics.
+func (m *Metrics) ObserveLogin(result string) {
+	if m == nil {
+		return
+	}
+	m.logins.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) ObserveUserRegistered() {
+	if m == nil {
+		return
+	}
+	m.usersRegistered.Inc()
+}
@@ -0,0 +1,53 @@
+// Package requestid carries the ID correlating a request with its logs
+// through context.Context.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header a request ID is accepted from and echoed in.
+const Header = "X-Request-ID"
+
+// maxLength bounds client supplied IDs so they cannot bloat every log line.
+const maxLength = 128
+
+type contextKey struct{}
+
+// New returns a fresh random request ID.
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether a client supplied ID is short and made of safe
+// characters only, so it can be logged and echoed verbatim.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/', r == '+', r == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext returns a copy of ctx carrying id.
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,54 @@
+// Package throttle limits how often something may happen per key, e.g. per
+// client IP or per email address, within one process.
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Window admits at most max events per key in each window, counted from the
+// first event of the key.
+type Window struct {
+	max    int
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	start time.Time
+	count int
+}
+
+func NewWindow(max int, window time.Duration) *Window {
+	return &Window{max: max, window: window, entries: make(map[string]*entry)}
+}
+
+// Allow records an event for key. When key is over the limit the event is
+// not recorded, and Allow returns false with the time left in the window.
+func (w *Window) Allow(key string) (bool, time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range w.entries {
+		if now.Sub(e.start) >= w.window {
+			delete(w.entries, k)
+		}
+	}
+
+	e, ok := w.entries[key]
+	if !ok {
+		e = &entry{start: now}
+		w.entries[key] = e
+	}
+
+	if e.count >= w.max {
+		return false, e.start.Add(w.window).Sub(now)
+	}
+	e.count++
+
+	return true, 0
+}
@@ -0,0 +1,44 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	w := NewWindow(2, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := w.Allow("a"); !ok {
+			t.Fatalf("event %d refused", i+1)
+		}
+	}
+
+	ok, retryAfter := w.Allow("a")
+	if ok {
+		t.Fatal("event over the limit allowed")
+	}
+	if retryAfter <= 0 || retryAfter > time.Hour {
+		t.Errorf("retry after %s, want within the window", retryAfter)
+	}
+
+	if ok, _ := w.Allow("b"); !ok {
+		t.Error("another key shares the limit")
+	}
+}
+
+func TestWindowExpires(t *testing.T) {
+	w := NewWindow(1, 10*time.Millisecond)
+
+	if ok, _ := w.Allow("a"); !ok {
+		t.Fatal("first event refused")
+	}
+	if ok, _ := w.Allow("a"); ok {
+		t.Fatal("second event allowed within the window")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if ok, _ := w.Allow("a"); !ok {
+		t.Error("event refused after the window passed")
+	}
+}
@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the HMAC-SHA1 seed of RFC 6238 appendix B, "12345678901234567890".
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+// The RFC vectors are 8 digits long. Code keeps value mod 10^6, which is the
+// last 6 digits of the same value. Only the SHA1 rows apply, since this
+// package implements HMAC-SHA1 only.
+func TestCodeRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		rfc  string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", tt.unix, err)
+		}
+		if want := tt.rfc[len(tt.rfc)-Digits:]; code != want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, code, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	previous, err := Code(rfcSecret, current-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tooOld, err := Code(rfcSecret, current-2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", "050471", current, true},
+		{"surrounding spaces", " 050471 ", current, true},
+		{"previous step within skew", previous, current - 1, true},
+		{"outside skew", tooOld, 0, false},
+		{"wrong code", "000000", 0, false},
+		{"rfc 8 digit code", "14050471", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now, 1)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate(%q) = %d, %v, want %d, %v", tt.code, step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code accepted a secret that is not base32")
+	}
+}